@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"os"
+	"slices"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/sniff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithAllowedFormats only accepts the videos whose container format, told by the magic bytes at their start,
+// is one of formats, named as sniff.Formats. Other videos are rejected with INVALID_ARGUMENT before reaching the disk
+// or ffmpeg, and the format of the accepted ones is passed to ffmpeg rather than probed.
+// Videos of any format are accepted and probed by ffmpeg otherwise.
+func WithAllowedFormats(formats []string) Option {
+	return func(s *GRPCServer) {
+		s.allowedFormats = formats
+	}
+}
+
+// detectFormat tells the container format of the video starting with head and checks that it is allowed.
+// The format is empty when no format is enforced.
+func (s *GRPCServer) detectFormat(head []byte) (string, error) {
+	if s.allowedFormats == nil {
+		return "", nil
+	}
+
+	format, err := sniff.Format(head)
+	switch {
+	case errors.Is(err, sniff.ErrShortHead):
+		return "", status.Errorf(codes.InvalidArgument, "video of %d bytes too short to tell its format", len(head))
+	case err != nil:
+		return "", status.Errorf(codes.InvalidArgument, "unrecognized video format, must be one of %v", s.allowedFormats)
+	case !slices.Contains(s.allowedFormats, format):
+		return "", status.Errorf(codes.InvalidArgument, "video format %s is not allowed, must be one of %v", format, s.allowedFormats)
+	}
+	return format, nil
+}
+
+// headComplete reports whether head, the start of a video, is long enough for detectFormat to tell its format.
+// Chunks are gathered until it is, the first one may be as short as the client likes.
+func (s *GRPCServer) headComplete(head []byte) bool {
+	if s.allowedFormats == nil {
+		return true
+	}
+
+	_, err := sniff.Format(head)
+	return !errors.Is(err, sniff.ErrShortHead)
+}
+
+// detectFileFormat tells the container format of the video in the file at path and checks that it is allowed.
+func (s *GRPCServer) detectFileFormat(path string) (string, error) {
+	if s.allowedFormats == nil {
+		return "", nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to read upload: %v", err)
+	}
+	defer f.Close()
+
+	head := make([]byte, sniff.HeadSize)
+
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", status.Errorf(codes.Internal, "failed to read upload: %v", err)
+	}
+	return s.detectFormat(head[:n])
+}
+
+// videoHead returns the video data carried by msgs.
+func videoHead(msgs []*apiv1.VideoData) []byte {
+	var head []byte
+	for _, msg := range msgs {
+		head = append(head, msg.GetData()...)
+	}
+	return head
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/sniff"
+)
+
+// webmHead is the start of a WebM file, its EBML header.
+const webmHead = "\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81"
+
+func TestExtractAudioAllowedFormats(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			assert.Equal(t, sniff.FormatMatroska, in.InputFormat)
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+		StreamAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput, r io.Reader, w io.Writer) (*audiostripper.MediaInfo, error) {
+			assert.Equal(t, sniff.FormatMatroska, in.InputFormat)
+			_, err := io.Copy(w, r)
+			return &audiostripper.MediaInfo{}, err
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService, WithAllowedFormats([]string{sniff.FormatMatroska}))
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	testCases := []struct {
+		name         string
+		opts         *apiv1.ExtractOptions
+		msgs         []*apiv1.VideoData
+		expectedCode codes.Code
+	}{
+		{
+			name:         "allowed format",
+			opts:         &apiv1.ExtractOptions{SampleRateHz: 44100},
+			msgs:         []*apiv1.VideoData{dataMsg(webmHead), dataMsg("videoData")},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "allowed format streamed",
+			opts:         &apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true},
+			msgs:         []*apiv1.VideoData{dataMsg(webmHead), dataMsg("videoData")},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "format not allowed",
+			opts:         &apiv1.ExtractOptions{SampleRateHz: 44100},
+			msgs:         []*apiv1.VideoData{dataMsg(mp4HeadHelper())},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "format not allowed streamed",
+			opts:         &apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true},
+			msgs:         []*apiv1.VideoData{dataMsg(mp4HeadHelper())},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "unknown format",
+			opts:         &apiv1.ExtractOptions{SampleRateHz: 44100},
+			msgs:         []*apiv1.VideoData{dataMsg("#!/bin/sh\nrm -rf /\n")},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "format told across chunks",
+			opts:         &apiv1.ExtractOptions{SampleRateHz: 44100},
+			msgs:         []*apiv1.VideoData{dataMsg(webmHead[:4]), dataMsg(webmHead[4:]), dataMsg("videoData")},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "format told across chunks streamed",
+			opts:         &apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true},
+			msgs:         []*apiv1.VideoData{dataMsg(webmHead[:4]), dataMsg(webmHead[4:]), dataMsg("videoData")},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "format not allowed across chunks",
+			opts:         &apiv1.ExtractOptions{SampleRateHz: 44100},
+			msgs:         []*apiv1.VideoData{dataMsg("RIFF"), dataMsg("\x24\x08\x00\x00WAVEfmt ")},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "video too short",
+			opts:         &apiv1.ExtractOptions{SampleRateHz: 44100},
+			msgs:         []*apiv1.VideoData{dataMsg("\x1a\x45")},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "video too short streamed",
+			opts:         &apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true},
+			msgs:         []*apiv1.VideoData{dataMsg("\x1a\x45")},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := extractAudioHelper(t, client, append([]*apiv1.VideoData{optionsMsg(tc.opts)}, tc.msgs...)...)
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestUploadSessionAllowedFormats(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			assert.Equal(t, sniff.FormatMatroska, in.InputFormat)
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService, WithAllowedFormats([]string{sniff.FormatMatroska}))
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	session, err := client.CreateUploadSession(context.TODO(), &apiv1.CreateUploadSessionRequest{})
+	require.NoError(t, err)
+
+	// The head is rejected before being written, even split over chunks
+	mp4Head := mp4HeadHelper()
+	_, err = uploadChunksHelper(t, client,
+		&apiv1.UploadChunk{SessionId: session.SessionId, Offset: 0, Data: []byte(mp4Head[:4])},
+		&apiv1.UploadChunk{SessionId: session.SessionId, Offset: 4, Data: []byte(mp4Head[4:])},
+	)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	got, err := client.GetUploadSession(context.TODO(), &apiv1.GetUploadSessionRequest{SessionId: session.SessionId})
+	require.NoError(t, err)
+	assert.Zero(t, got.CommittedOffset)
+
+	// A head too short to tell the format is committed when the stream ends, all of it once told
+	got, err = uploadChunksHelper(t, client, &apiv1.UploadChunk{SessionId: session.SessionId, Offset: 0, Data: []byte(webmHead[:4])})
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, got.CommittedOffset)
+
+	got, err = uploadChunksHelper(t, client,
+		&apiv1.UploadChunk{SessionId: session.SessionId, Offset: 4, Data: []byte(webmHead[4:])},
+		&apiv1.UploadChunk{SessionId: session.SessionId, Offset: uint64(len(webmHead)), Data: []byte("videoData")},
+	)
+	require.NoError(t, err)
+	assert.EqualValues(t, len(webmHead)+len("videoData"), got.CommittedOffset)
+
+	_, err = extractAudioHelper(t, client, optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100, UploadSessionId: session.SessionId}))
+	require.NoError(t, err)
+}
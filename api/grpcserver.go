@@ -2,38 +2,130 @@ package api
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"io"
 	"os"
+	"strconv"
+	"time"
 
 	"log/slog"
 
-	"github.com/alesr/audiostripper"
 	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/app/jobs"
+	"github.com/alesr/audiostrippersvc/internal/app/uploads"
+	"github.com/alesr/audiostrippersvc/internal/sniff"
+	"github.com/alesr/audiostrippersvc/internal/workers"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 const (
-	MaxInMemorySize = 5 << 20 // 5MB memory threshold
+	MaxInMemorySize = 5 << 20 // 5MB memory threshold, see WithMaxInMemorySize
 	chunkSize       = 5 << 20 // 5MB chunk for sending data back to client
+
+	// uploadProgressInterval is the minimum interval between two STAGE_UPLOADING progress updates.
+	uploadProgressInterval = time.Second
+
+	// errorDomain is the domain of the ErrorInfo details of the errors, see https://google.aip.dev/193.
+	errorDomain = "audiostrippersvc"
 )
 
+// failureReasons are the ErrorInfo reasons of the failures of the extraction commands, by cause.
+var failureReasons = map[error]string{
+	audiostripper.ErrInvalidData:      "INVALID_DATA",
+	audiostripper.ErrNoAudio:          "NO_AUDIO_STREAM",
+	audiostripper.ErrUnsupportedCodec: "UNSUPPORTED_CODEC",
+	audiostripper.ErrDiskFull:         "DISK_FULL",
+	audiostripper.ErrResourceLimit:    "RESOURCE_LIMIT",
+}
+
+var formats = map[apiv1.AudioFormat]audiostripper.Format{
+	apiv1.AudioFormat_AUDIO_FORMAT_UNSPECIFIED: audiostripper.FormatWAV,
+	apiv1.AudioFormat_AUDIO_FORMAT_WAV:         audiostripper.FormatWAV,
+	apiv1.AudioFormat_AUDIO_FORMAT_FLAC:        audiostripper.FormatFLAC,
+	apiv1.AudioFormat_AUDIO_FORMAT_MP3:         audiostripper.FormatMP3,
+	apiv1.AudioFormat_AUDIO_FORMAT_OPUS:        audiostripper.FormatOpus,
+	apiv1.AudioFormat_AUDIO_FORMAT_AAC:         audiostripper.FormatAAC,
+}
+
+var channelLayouts = map[apiv1.ChannelLayout]audiostripper.ChannelLayout{
+	apiv1.ChannelLayout_CHANNEL_LAYOUT_UNSPECIFIED:  audiostripper.ChannelsStereo,
+	apiv1.ChannelLayout_CHANNEL_LAYOUT_MONO:         audiostripper.ChannelsMono,
+	apiv1.ChannelLayout_CHANNEL_LAYOUT_STEREO:       audiostripper.ChannelsStereo,
+	apiv1.ChannelLayout_CHANNEL_LAYOUT_SURROUND_5_1: audiostripper.ChannelsSurround51,
+	apiv1.ChannelLayout_CHANNEL_LAYOUT_SOURCE:       audiostripper.ChannelsSource,
+}
+
+var streamTypes = map[string]apiv1.StreamType{
+	audiostripper.StreamTypeAudio:      apiv1.StreamType_STREAM_TYPE_AUDIO,
+	audiostripper.StreamTypeVideo:      apiv1.StreamType_STREAM_TYPE_VIDEO,
+	audiostripper.StreamTypeSubtitle:   apiv1.StreamType_STREAM_TYPE_SUBTITLE,
+	audiostripper.StreamTypeData:       apiv1.StreamType_STREAM_TYPE_DATA,
+	audiostripper.StreamTypeAttachment: apiv1.StreamType_STREAM_TYPE_ATTACHMENT,
+}
+
 type audioStripperService interface {
 	ExtractAudio(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error)
+	StreamAudio(ctx context.Context, in *audiostripper.ExtractAudioInput, r io.Reader, w io.Writer) (*audiostripper.MediaInfo, error)
+	ProbeMedia(ctx context.Context, in *audiostripper.ProbeMediaInput) (*audiostripper.MediaInfo, error)
 }
 
 type GRPCServer struct {
 	apiv1.UnimplementedAudioStripperServer
-	logger  *slog.Logger
-	service audioStripperService
+	logger          *slog.Logger
+	service         audioStripperService
+	jobs            *jobs.Manager
+	uploads         *uploads.Manager
+	maxInMemorySize int    // Size up to which uploads are piped to the extraction from memory
+	workDir         string // Directory of the temp files, empty for the default temp directory
+
+	maxUploadSize        uint64            // Zero when unlimited
+	apiKeyMaxUploadSizes map[string]uint64 // By API key, in place of maxUploadSize
+
+	allowedFormats []string // Container formats of the videos accepted, nil to accept any
+
+	workers    *workers.Pool // nil to run the extractions right away
+	retryAfter time.Duration
+}
+
+// Option configures a GRPCServer.
+type Option func(*GRPCServer)
+
+// WithMaxInMemorySize sets the size up to which uploads are kept in memory and piped to ffmpeg
+// rather than written to disk, MaxInMemorySize by default. Zero always writes them to disk.
+func WithMaxInMemorySize(size int) Option {
+	return func(s *GRPCServer) {
+		s.maxInMemorySize = size
+	}
+}
+
+// WithWorkDir sets the directory the uploads and extracted audio are written to while
+// a request is handled, the default temp directory otherwise.
+func WithWorkDir(dir string) Option {
+	return func(s *GRPCServer) {
+		s.workDir = dir
+	}
 }
 
-func NewGRPCServer(logger *slog.Logger, service audioStripperService) *GRPCServer {
-	return &GRPCServer{
-		logger:  logger,
-		service: service,
+func NewGRPCServer(logger *slog.Logger, service audioStripperService, jobManager *jobs.Manager, uploadSessions *uploads.Manager, opts ...Option) *GRPCServer {
+	s := GRPCServer{
+		logger:          logger,
+		service:         service,
+		jobs:            jobManager,
+		uploads:         uploadSessions,
+		maxInMemorySize: MaxInMemorySize,
+	}
+
+	for _, opt := range opts {
+		opt(&s)
 	}
+	return &s
 }
 
 func (s *GRPCServer) Register(server *grpc.Server) {
@@ -42,58 +134,278 @@ func (s *GRPCServer) Register(server *grpc.Server) {
 }
 
 func (s *GRPCServer) ExtractAudio(stream apiv1.AudioStripper_ExtractAudioServer) error {
-	var sampleRate string
+	first, opts, input, err := s.receiveOptions(stream)
+	if err != nil {
+		return err
+	}
+
+	var (
+		video     videoStream = stream
+		memLimit  int         // Uploads that can be piped to the extraction are kept in memory up to this size
+		canStream = streamable(input, opts)
+	)
 
-	// Create a temp file for the incoming data
-	tempFile, err := os.CreateTemp("", "input-*")
+	if canStream {
+		memLimit = s.maxInMemorySize
+	}
+
+	// Verifying the upload takes all of it, which streaming would only have once ffmpeg ran on it
+	if opts.Streaming && canStream && opts.ExpectedSize == 0 && opts.ExpectedSha256 == "" {
+		msgs, needsSeeking, err := s.receiveHead(stream, first)
+		if err != nil {
+			return err
+		}
+
+		if input.InputFormat, err = s.detectFormat(videoHead(msgs)); err != nil {
+			return err
+		}
+
+		// The messages received to sniff the video are replayed to whichever path extracts it
+		replay := &replayStream{msgs: msgs[1:], stream: stream}
+		if !needsSeeking {
+			return s.streamAudio(stream, replay, first, input)
+		}
+
+		s.logger.Info("Video needs seeking, extracting from the complete upload")
+		video = replay
+	}
+
+	uploading := uploadProgress{stream: stream}
+
+	up, err := s.receiveVideo(video, first, opts, memLimit, uploading.update)
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to create temp file: %v", err)
+		return err
 	}
 
-	// Loop to receive streamed data and write to temp file
-	for {
-		chunk, err := stream.Recv()
-		if err == io.EOF {
-			break
+	if err := uploading.done(up.size); err != nil {
+		return err
+	}
+
+	input.InputFormat = up.format
+
+	// Small uploads never touch the disk, unless the video needs seeking
+	if up.path == "" {
+		if needsSeeking, err := sniff.NeedsSeeking(up.data); err == nil && !needsSeeking {
+			return s.extractInMemory(stream, up, input)
 		}
-		if err != nil {
-			return status.Errorf(codes.Unknown, "failed to receive data: %v", err)
+
+		if up.path, err = writeTempFile(s.workDir, up.data); err != nil {
+			return status.Errorf(codes.Internal, "failed to create temp file: %v", err)
 		}
+	}
+
+	defer s.removeTempFile(up.path)
+
+	input.FilePath = up.path
 
-		// Capture sample rate from the first chunk
-		if sampleRate == "" {
-			sampleRate = chunk.SampleRate
+	input.OnProgress = func(p audiostripper.Progress) {
+		progress := apiv1.Progress{
+			Stage:         apiv1.Stage_STAGE_EXTRACTING,
+			BytesReceived: up.size,
+			OutTime:       durationpb.New(p.OutTime),
+			Percent:       float32(p.Percent()),
 		}
 
-		if _, err = tempFile.Write(chunk.Data); err != nil {
-			return status.Errorf(codes.Internal, "failed to write to temp file: %v", err)
+		// A failed send means the client is gone, the extraction result will fail to send as well
+		if err := sendProgress(stream, &progress); err != nil {
+			s.logger.Warn("Failed to send progress", slog.String("error", err.Error()))
 		}
 	}
 
-	if err := tempFile.Close(); err != nil {
-		return status.Errorf(codes.Internal, "failed to close temp file: %v", err)
+	release, err := s.acquireWorker(stream)
+	if err != nil {
+		return err
 	}
 
 	// Call the service to extract audio
-	output, err := s.service.ExtractAudio(
-		stream.Context(),
-		&audiostripper.ExtractAudioInput{
-			SampleRate: sampleRate,
-			FilePath:   tempFile.Name(),
-		},
-	)
+	output, err := s.service.ExtractAudio(stream.Context(), input)
+	release()
+
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to extract audio: %v", err)
+		return s.extractionError(stream.Context(), err)
 	}
 
-	outputFile, err := os.Open(output.FilePath)
+	defer func() {
+		for _, path := range resultFiles(output) {
+			s.removeTempFile(path)
+		}
+	}()
+
+	if err := sendProgress(stream, &apiv1.Progress{Stage: apiv1.Stage_STAGE_SENDING, BytesReceived: up.size}); err != nil {
+		return err
+	}
+	return s.sendOutput(stream, output)
+}
+
+// removeTempFile removes a temp file of a request, which may already be gone.
+func (s *GRPCServer) removeTempFile(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.logger.Error("Failed to remove temp file", slog.String("file", path), slog.String("error", err.Error()))
+	}
+}
+
+// extractionRequest is an extraction received from a client.
+type extractionRequest struct {
+	input *audiostripper.ExtractAudioInput // With FilePath set to the received video
+	opts  *apiv1.ExtractOptions
+	size  uint64 // Size of the received video in bytes
+}
+
+// receiveExtractionRequest receives the options header and validates the extraction options
+// before touching the disk, then receives the video.
+// onChunk, if set, is called with the number of bytes received so far after every chunk.
+func (s *GRPCServer) receiveExtractionRequest(stream videoStream, onChunk func(received uint64) error) (*extractionRequest, error) {
+	msg, opts, input, err := s.receiveOptions(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	up, err := s.receiveVideo(stream, msg, opts, 0, onChunk)
+	if err != nil {
+		return nil, err
+	}
+
+	input.FilePath = up.path
+	input.InputFormat = up.format
+	return &extractionRequest{input: input, opts: opts, size: up.size}, nil
+}
+
+// receiveOptions receives the first message of the stream and validates the extraction options it carries.
+// The message is returned as it may carry the first chunk of the video as well.
+func (s *GRPCServer) receiveOptions(stream videoStream) (*apiv1.VideoData, *apiv1.ExtractOptions, *audiostripper.ExtractAudioInput, error) {
+	msg, err := stream.Recv()
+	if err == io.EOF {
+		return nil, nil, nil, status.Error(codes.InvalidArgument, "no video data received")
+	}
+	if err != nil {
+		return nil, nil, nil, status.Errorf(codes.Unknown, "failed to receive data: %v", err)
+	}
+
+	opts, err := optionsFromHeader(msg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	input, err := newExtractAudioInput(opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := validateIntegrityOptions(opts); err != nil {
+		return nil, nil, nil, err
+	}
+
+	s.logger.Info("Extracting audio",
+		slog.String("format", string(input.Format)),
+		slog.Int("sample_rate", input.SampleRate),
+		slog.String("channels", string(input.Channels)),
+		slog.Bool("streaming", opts.Streaming),
+		slog.Any("client_metadata", opts.ClientMetadata),
+	)
+	return msg, opts, input, nil
+}
+
+// extractionError converts an error of the extraction service into a gRPC status, see serviceError.
+func (s *GRPCServer) extractionError(ctx context.Context, err error) error {
+	return s.serviceError(ctx, "failed to extract audio", err)
+}
+
+// serviceError converts an error of the extraction service into a gRPC status prefixed with msg.
+// Requests interrupted by the end of ctx, the client having gone or its deadline having passed, are logged with the cause.
+// The failures of the extraction commands are detailed with an ErrorInfo giving their cause and the end of their log.
+func (s *GRPCServer) serviceError(ctx context.Context, msg string, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		s.logger.Warn("Request interrupted", slog.String("error", msg), slog.Any("cause", context.Cause(ctx)))
+		return status.FromContextError(err).Err()
+	}
+
+	st := status.Newf(extractionCode(err), "%s: %v", msg, err)
+
+	var cmdErr *audiostripper.CmdError
+	if !errors.As(err, &cmdErr) {
+		return st.Err()
+	}
+
+	reason, ok := failureReasons[cmdErr.Kind]
+	if !ok {
+		reason = "COMMAND_FAILED"
+	}
+
+	detailed, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   errorDomain,
+		Metadata: map[string]string{"command": cmdErr.Cmd, "stderr": cmdErr.Stderr},
+	})
+	if detailsErr != nil {
+		s.logger.Error("Failed to add error details", slog.String("error", detailsErr.Error()))
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// extractionCode returns the gRPC code of an error of the extraction service.
+func extractionCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, audiostripper.ErrInvalidInput), errors.Is(err, audiostripper.ErrInvalidData):
+		return codes.InvalidArgument
+	case errors.Is(err, audiostripper.ErrOutOfRange):
+		return codes.OutOfRange
+	case errors.Is(err, audiostripper.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, audiostripper.ErrTooLong), errors.Is(err, audiostripper.ErrNoAudio), errors.Is(err, audiostripper.ErrUnsupportedCodec):
+		return codes.FailedPrecondition
+	case errors.Is(err, audiostripper.ErrDiskFull), errors.Is(err, audiostripper.ErrResourceLimit):
+		return codes.ResourceExhausted
+	case errors.Is(err, audiostripper.ErrTimeout):
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+// audioStream is the sending side of the RPCs streaming extracted audio.
+type audioStream interface {
+	Send(*apiv1.AudioData) error
+}
+
+// sendOutput streams the extracted audio to the client, every track preceded by its header
+// when all audio streams were extracted.
+func (s *GRPCServer) sendOutput(stream audioStream, output *audiostripper.ExtractAudioOutput) error {
+	if len(output.Tracks) == 0 {
+		return s.sendAudio(stream, output.FilePath, output.Media)
+	}
+
+	for _, track := range output.Tracks {
+		header := apiv1.Track{
+			StreamIndex: uint32(track.Stream.Index),
+			Language:    track.Stream.Language,
+			Codec:       track.Stream.Codec,
+		}
+
+		if err := stream.Send(&apiv1.AudioData{Payload: &apiv1.AudioData_Track{Track: &header}}); err != nil {
+			return status.Errorf(codes.Internal, "failed to send track to client: %s", err)
+		}
+
+		if err := s.sendAudio(stream, track.FilePath, track.Media); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendAudio streams the extracted audio file to the client followed by its summary.
+func (s *GRPCServer) sendAudio(stream audioStream, path string, media *audiostripper.MediaInfo) error {
+	outputFile, err := os.Open(path)
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to open output file: %v", err)
 	}
 	defer outputFile.Close()
 
+	hash := sha256.New()
 	buffer := make([]byte, chunkSize)
 
+	summary := newSummary(media)
+
 	for {
 		bytesRead, err := outputFile.Read(buffer)
 		if err == io.EOF {
@@ -104,13 +416,299 @@ func (s *GRPCServer) ExtractAudio(stream apiv1.AudioStripper_ExtractAudioServer)
 		}
 
 		// Send the chunk to the client
-		if err := stream.Send(&apiv1.AudioData{Data: buffer[:bytesRead]}); err != nil {
+		if err := stream.Send(&apiv1.AudioData{Payload: &apiv1.AudioData_Data{Data: buffer[:bytesRead]}}); err != nil {
 			return status.Errorf(codes.Internal, "failed to send chunk to client: %s", err)
 		}
+
+		hash.Write(buffer[:bytesRead])
+		summary.TotalBytes += uint64(bytesRead)
+	}
+
+	summary.Sha256 = hex.EncodeToString(hash.Sum(nil))
+
+	if err := stream.Send(&apiv1.AudioData{Payload: &apiv1.AudioData_Summary{Summary: summary}}); err != nil {
+		return status.Errorf(codes.Internal, "failed to send summary to client: %s", err)
+	}
+	return nil
+}
+
+// sendAudioRange streams length bytes of the extracted audio file from offset, or up to the end of the file
+// for a zero length, followed by the summary of the whole file.
+func (s *GRPCServer) sendAudioRange(stream audioStream, path string, media *audiostripper.MediaInfo, offset, length uint64) error {
+	outputFile, err := os.Open(path)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to open output file: %v", err)
+	}
+	defer outputFile.Close()
+
+	info, err := outputFile.Stat()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to stat output file: %v", err)
+	}
+
+	size := uint64(info.Size())
+	if offset > size {
+		return status.Errorf(codes.OutOfRange, "offset %d is beyond the end of the %d bytes audio", offset, size)
+	}
+
+	if length == 0 || offset+length > size {
+		length = size - offset
+	}
+
+	section := io.NewSectionReader(outputFile, int64(offset), int64(length))
+	buffer := make([]byte, chunkSize)
+
+	for {
+		bytesRead, err := section.Read(buffer)
+		if bytesRead > 0 {
+			if err := stream.Send(&apiv1.AudioData{Payload: &apiv1.AudioData_Data{Data: buffer[:bytesRead]}}); err != nil {
+				return status.Errorf(codes.Internal, "failed to send chunk to client: %s", err)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read from output file: %s", err)
+		}
 	}
 
-	if err := os.Remove(output.FilePath); err != nil {
-		s.logger.Error("Failed to remove temp output file", slog.String("file", output.FilePath), slog.String("error", err.Error()))
+	// The summary describes the whole file, for the client to verify the reassembled audio
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to read from output file: %s", err)
+	}
+
+	summary := newSummary(media)
+	summary.TotalBytes = size
+	summary.Sha256 = hex.EncodeToString(checksum)
+
+	if err := stream.Send(&apiv1.AudioData{Payload: &apiv1.AudioData_Summary{Summary: summary}}); err != nil {
+		return status.Errorf(codes.Internal, "failed to send summary to client: %s", err)
 	}
 	return nil
 }
+
+func (s *GRPCServer) ProbeMedia(stream apiv1.AudioStripper_ProbeMediaServer) error {
+	msg, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "no video data received")
+	}
+	if err != nil {
+		return status.Errorf(codes.Unknown, "failed to receive data: %v", err)
+	}
+
+	// The options header is optional, only its integrity options apply to probing
+	opts := msg.GetOptions()
+	if opts == nil {
+		opts = &apiv1.ExtractOptions{}
+	}
+
+	if err := validateIntegrityOptions(opts); err != nil {
+		return err
+	}
+
+	up, err := s.receiveVideo(stream, msg, opts, 0, nil)
+	if err != nil {
+		return err
+	}
+
+	defer s.removeTempFile(up.path)
+
+	// ffprobe runs on the workers of the extractions, as ffmpeg does
+	release, err := s.acquireWorker(stream)
+	if err != nil {
+		return err
+	}
+
+	info, err := s.service.ProbeMedia(stream.Context(), &audiostripper.ProbeMediaInput{FilePath: up.path, InputFormat: up.format})
+	release()
+
+	if err != nil {
+		return s.serviceError(stream.Context(), "failed to probe media", err)
+	}
+
+	if err := stream.SendAndClose(newMediaInfo(info)); err != nil {
+		return status.Errorf(codes.Internal, "failed to send media info to client: %s", err)
+	}
+	return nil
+}
+
+// timeRange returns the range of the video to extract, a zero end meaning until the end of the video.
+func timeRange(opts *apiv1.ExtractOptions) (start, end time.Duration, err error) {
+	if opts.StartOffset != nil {
+		if err := opts.StartOffset.CheckValid(); err != nil {
+			return 0, 0, status.Errorf(codes.InvalidArgument, "invalid start offset: %v", err)
+		}
+		start = opts.StartOffset.AsDuration()
+	}
+
+	switch e := opts.End.(type) {
+	case *apiv1.ExtractOptions_Duration:
+		if err := e.Duration.CheckValid(); err != nil || e.Duration.AsDuration() <= 0 {
+			return 0, 0, status.Error(codes.InvalidArgument, "invalid duration: must be positive")
+		}
+		end = start + e.Duration.AsDuration()
+	case *apiv1.ExtractOptions_EndTime:
+		if err := e.EndTime.CheckValid(); err != nil || e.EndTime.AsDuration() <= 0 {
+			return 0, 0, status.Error(codes.InvalidArgument, "invalid end time: must be positive")
+		}
+		end = e.EndTime.AsDuration()
+	}
+	return start, end, nil
+}
+
+// streamSelector returns the audio stream selected by the options, or nil to let ffmpeg pick one.
+func streamSelector(opts *apiv1.ExtractOptions) *audiostripper.StreamSelector {
+	switch s := opts.Stream.(type) {
+	case *apiv1.ExtractOptions_StreamIndex:
+		return &audiostripper.StreamSelector{Index: int(s.StreamIndex)}
+	case *apiv1.ExtractOptions_StreamLanguage:
+		return &audiostripper.StreamSelector{Language: s.StreamLanguage}
+	}
+	return nil
+}
+
+// newMediaInfo converts the media description of the service into its API representation.
+func newMediaInfo(info *audiostripper.MediaInfo) *apiv1.MediaInfo {
+	out := apiv1.MediaInfo{
+		FormatName: info.FormatName,
+		Duration:   durationpb.New(info.Duration),
+		BitRate:    uint64(info.BitRate),
+	}
+
+	for _, stream := range info.Streams {
+		out.Streams = append(out.Streams, &apiv1.StreamInfo{
+			Index:        uint32(stream.Index),
+			Type:         streamTypes[stream.Type],
+			Codec:        stream.Codec,
+			SampleRateHz: uint32(stream.SampleRate),
+			Channels:     uint32(stream.Channels),
+			Language:     stream.Language,
+		})
+	}
+	return &out
+}
+
+// newSummary returns a summary describing the extracted audio.
+// The byte count and checksum are filled in while the audio is sent.
+func newSummary(media *audiostripper.MediaInfo) *apiv1.Summary {
+	if media == nil {
+		return &apiv1.Summary{}
+	}
+
+	summary := apiv1.Summary{Duration: durationpb.New(media.Duration)}
+
+	if streams := media.AudioStreams(); len(streams) > 0 {
+		summary.SampleRateHz = uint32(streams[0].SampleRate)
+		summary.Channels = uint32(streams[0].Channels)
+		summary.Codec = streams[0].Codec
+	}
+	return &summary
+}
+
+// sendProgress sends a progress update to the client.
+func sendProgress(stream apiv1.AudioStripper_ExtractAudioServer, progress *apiv1.Progress) error {
+	if err := stream.Send(&apiv1.AudioData{Payload: &apiv1.AudioData_Progress{Progress: progress}}); err != nil {
+		return status.Errorf(codes.Internal, "failed to send progress to client: %s", err)
+	}
+	return nil
+}
+
+// uploadProgress sends the STAGE_UPLOADING progress of an upload to the client.
+// Sends block once the flow control window of the stream is full, so clients sending the whole video
+// before reading the response would stall their own upload if every chunk was reported:
+// updates are sent at most once every uploadProgressInterval.
+type uploadProgress struct {
+	stream   apiv1.AudioStripper_ExtractAudioServer
+	sentAt   time.Time // When the last update was sent
+	reported uint64    // Number of bytes reported by the last update
+}
+
+// update is the onChunk callback of receiveVideo.
+func (p *uploadProgress) update(received uint64) error {
+	if time.Since(p.sentAt) < uploadProgressInterval {
+		return nil
+	}
+	return p.send(received)
+}
+
+// done reports the complete upload of size bytes, unless the last update did.
+// The client is done sending by then, so the send can't stall the upload.
+func (p *uploadProgress) done(size uint64) error {
+	if size == p.reported {
+		return nil
+	}
+	return p.send(size)
+}
+
+func (p *uploadProgress) send(received uint64) error {
+	if err := sendProgress(p.stream, &apiv1.Progress{Stage: apiv1.Stage_STAGE_UPLOADING, BytesReceived: received}); err != nil {
+		return err
+	}
+
+	p.sentAt = time.Now()
+	p.reported = received
+	return nil
+}
+
+// optionsFromHeader returns the extraction options carried by the first message of a stream.
+// Clients that predate ExtractOptions send the options alongside the first data chunk,
+// so a first message with legacy options set is accepted as a header as well.
+func optionsFromHeader(msg *apiv1.VideoData) (*apiv1.ExtractOptions, error) {
+	if opts := msg.GetOptions(); opts != nil {
+		return opts, nil
+	}
+
+	if msg.SampleRateHz == 0 && msg.SampleRate == "" {
+		return nil, status.Error(codes.InvalidArgument, "first message must carry the extraction options")
+	}
+
+	opts := apiv1.ExtractOptions{
+		Format:       msg.Format,
+		SampleRateHz: msg.SampleRateHz,
+		Channels:     msg.Channels,
+	}
+
+	if opts.SampleRateHz == 0 {
+		sampleRate, err := strconv.ParseUint(msg.SampleRate, 10, 32)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid sample rate %q: must be a number of Hz", msg.SampleRate)
+		}
+		opts.SampleRateHz = uint32(sampleRate)
+	}
+	return &opts, nil
+}
+
+// newExtractAudioInput builds and validates the extraction input from the stream options.
+func newExtractAudioInput(opts *apiv1.ExtractOptions) (*audiostripper.ExtractAudioInput, error) {
+	format, ok := formats[opts.Format]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported audio format: %s", opts.Format)
+	}
+
+	channels, ok := channelLayouts[opts.Channels]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported channel layout: %s", opts.Channels)
+	}
+
+	start, end, err := timeRange(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	input := audiostripper.ExtractAudioInput{
+		SampleRate: int(opts.SampleRateHz),
+		Format:     format,
+		Channels:   channels,
+		Start:      start,
+		End:        end,
+		Stream:     streamSelector(opts),
+		AllStreams: opts.GetAllStreams(),
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid extraction options: %v", err)
+	}
+	return &input, nil
+}
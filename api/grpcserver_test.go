@@ -2,37 +2,62 @@ package api
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/alesr/audiostripper"
 	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/app/jobs"
+	"github.com/alesr/audiostrippersvc/internal/app/uploads"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 var _ audioStripperService = &mockAudioStripperService{}
 
 type mockAudioStripperService struct {
 	ExtractAudioFunc func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error)
+	StreamAudioFunc  func(ctx context.Context, in *audiostripper.ExtractAudioInput, r io.Reader, w io.Writer) (*audiostripper.MediaInfo, error)
+	ProbeMediaFunc   func(ctx context.Context, in *audiostripper.ProbeMediaInput) (*audiostripper.MediaInfo, error)
 }
 
 func (m *mockAudioStripperService) ExtractAudio(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
 	return m.ExtractAudioFunc(ctx, in)
 }
 
+func (m *mockAudioStripperService) StreamAudio(ctx context.Context, in *audiostripper.ExtractAudioInput, r io.Reader, w io.Writer) (*audiostripper.MediaInfo, error) {
+	return m.StreamAudioFunc(ctx, in, r, w)
+}
+
+func (m *mockAudioStripperService) ProbeMedia(ctx context.Context, in *audiostripper.ProbeMediaInput) (*audiostripper.MediaInfo, error) {
+	return m.ProbeMediaFunc(ctx, in)
+}
+
 func TestExtractAudio(t *testing.T) {
 	mockService := mockAudioStripperService{}
 
 	mockService.ExtractAudioFunc = func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
 		require.NotEmpty(t, in.FilePath)
-		require.Equal(t, "44100", in.SampleRate)
+		require.Equal(t, 44100, in.SampleRate)
+		require.Equal(t, audiostripper.FormatWAV, in.Format)
+		require.Equal(t, audiostripper.ChannelsStereo, in.Channels)
 
 		// Read the input file
 		inputFile, err := os.Open(in.FilePath)
@@ -71,14 +96,15 @@ func TestExtractAudio(t *testing.T) {
 	videoDataStream, err := client.ExtractAudio(context.TODO())
 	require.NoError(t, err)
 
-	err = videoDataStream.Send(&apiv1.VideoData{
-		SampleRate: "44100", // We only care about sample rate once
-		Data:       []byte("videoDataChunk1"),
-	})
+	// The first message carries the extraction options
+	err = videoDataStream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100}))
+	require.NoError(t, err)
+
+	err = videoDataStream.Send(dataMsg("videoDataChunk1"))
 	require.NoError(t, err)
 
 	// Send more video data
-	err = videoDataStream.Send(&apiv1.VideoData{Data: []byte("videoDataChunk2")})
+	err = videoDataStream.Send(dataMsg("videoDataChunk2"))
 	require.NoError(t, err)
 
 	err = videoDataStream.CloseSend()
@@ -95,21 +121,978 @@ func TestExtractAudio(t *testing.T) {
 		}
 		require.NoError(t, err)
 
-		allReceivedData = append(allReceivedData, receivedData.Data...)
+		allReceivedData = append(allReceivedData, receivedData.GetData()...)
 	}
 
 	// The allReceivedData is the result of the ffmpeg process.69
 	require.Equal(t, []byte("someRandomAudioData"), allReceivedData)
 }
 
+func TestExtractAudioLegacyHeader(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			require.Equal(t, 44100, in.SampleRate)
+			require.Equal(t, audiostripper.FormatFLAC, in.Format)
+
+			// The data sent alongside the legacy options is part of the upload
+			inputFileData, err := os.ReadFile(in.FilePath)
+			require.NoError(t, err)
+			require.Equal(t, []byte("videoDataChunk1videoDataChunk2"), inputFileData)
+
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	_, err := extractAudioHelper(t, client,
+		&apiv1.VideoData{
+			SampleRate: "44100",
+			Format:     apiv1.AudioFormat_AUDIO_FORMAT_FLAC,
+			Payload:    &apiv1.VideoData_Data{Data: []byte("videoDataChunk1")},
+		},
+		dataMsg("videoDataChunk2"),
+	)
+	require.NoError(t, err)
+}
+
+func TestExtractAudioHeader(t *testing.T) {
+	testCases := []struct {
+		name  string
+		given []*apiv1.VideoData
+	}{
+		{
+			name:  "empty stream",
+			given: nil,
+		},
+		{
+			name:  "first message is a data chunk",
+			given: []*apiv1.VideoData{dataMsg("videoData")},
+		},
+		{
+			name: "options sent twice",
+			given: []*apiv1.VideoData{
+				optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100}),
+				dataMsg("videoData"),
+				optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 48000}),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := mockAudioStripperService{
+				ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+					t.Error("service must not be called without a valid header")
+					return nil, errors.New("unexpected call")
+				},
+			}
+
+			server, lis := makeGRPCServerHelper(t, &mockService)
+			defer server.Stop()
+
+			client := makeGRPCClientHelper(t, lis)
+
+			_, err := extractAudioHelper(t, client, tc.given...)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestExtractAudioFormat(t *testing.T) {
+	testCases := []struct {
+		name         string
+		givenFormat  apiv1.AudioFormat
+		givenRate    uint32
+		expectedCode codes.Code
+	}{
+		{
+			name:         "flac is forwarded to the service",
+			givenFormat:  apiv1.AudioFormat_AUDIO_FORMAT_FLAC,
+			givenRate:    44100,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "unknown format is rejected",
+			givenFormat:  apiv1.AudioFormat(42),
+			givenRate:    44100,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "opus with unsupported sample rate is rejected",
+			givenFormat:  apiv1.AudioFormat_AUDIO_FORMAT_OPUS,
+			givenRate:    44100,
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := mockAudioStripperService{
+				ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+					require.Equal(t, audiostripper.FormatFLAC, in.Format)
+					return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+				},
+			}
+
+			server, lis := makeGRPCServerHelper(t, &mockService)
+			defer server.Stop()
+
+			client := makeGRPCClientHelper(t, lis)
+
+			_, err := extractAudioHelper(t, client,
+				optionsMsg(&apiv1.ExtractOptions{SampleRateHz: tc.givenRate, Format: tc.givenFormat}),
+				dataMsg("videoData"),
+			)
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestExtractAudioSampleRate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		given        *apiv1.VideoData
+		expectedRate int
+		expectedCode codes.Code
+	}{
+		{
+			name:         "numeric sample rate",
+			given:        optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 16000}),
+			expectedRate: 16000,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "numeric sample rate takes precedence over the deprecated field",
+			given:        &apiv1.VideoData{SampleRateHz: 16000, SampleRate: "44100"},
+			expectedRate: 16000,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "missing sample rate",
+			given:        optionsMsg(&apiv1.ExtractOptions{}),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "malformed deprecated sample rate",
+			given:        &apiv1.VideoData{SampleRate: "44.1kHz"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "unsupported sample rate",
+			given:        optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 12345}),
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := mockAudioStripperService{
+				ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+					require.Equal(t, tc.expectedRate, in.SampleRate)
+					return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+				},
+			}
+
+			server, lis := makeGRPCServerHelper(t, &mockService)
+			defer server.Stop()
+
+			client := makeGRPCClientHelper(t, lis)
+
+			_, err := extractAudioHelper(t, client, tc.given, dataMsg("videoData"))
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestExtractAudioProgress(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			require.NotNil(t, in.OnProgress)
+			in.OnProgress(audiostripper.Progress{OutTime: 5 * time.Second, Duration: 10 * time.Second})
+
+			require.NoError(t, os.WriteFile(in.FilePath, []byte("audioData"), 0o600))
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	stream, err := client.ExtractAudio(context.TODO())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100})))
+	require.NoError(t, stream.Send(dataMsg("videoDataChunk1")))
+	require.NoError(t, stream.Send(dataMsg("videoDataChunk2")))
+	require.NoError(t, stream.CloseSend())
+
+	var progress []*apiv1.Progress
+	var audio []byte
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		if p := msg.GetProgress(); p != nil {
+			progress = append(progress, p)
+		}
+		audio = append(audio, msg.GetData()...)
+	}
+
+	require.Equal(t, []byte("audioData"), audio)
+	require.Len(t, progress, 4)
+
+	assert.Equal(t, apiv1.Stage_STAGE_UPLOADING, progress[0].Stage)
+	assert.Equal(t, uint64(15), progress[0].BytesReceived)
+
+	assert.Equal(t, apiv1.Stage_STAGE_UPLOADING, progress[1].Stage)
+	assert.Equal(t, uint64(30), progress[1].BytesReceived)
+
+	assert.Equal(t, apiv1.Stage_STAGE_EXTRACTING, progress[2].Stage)
+	assert.Equal(t, 5*time.Second, progress[2].OutTime.AsDuration())
+	assert.Equal(t, float32(50), progress[2].Percent)
+
+	assert.Equal(t, apiv1.Stage_STAGE_SENDING, progress[3].Stage)
+}
+
+func TestExtractAudioUploadBeforeReading(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			require.NoError(t, os.WriteFile(in.FilePath, []byte("audioData"), 0o600))
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.ExtractAudio(ctx)
+	require.NoError(t, err)
+
+	// Reporting every chunk would queue as many progress messages for a client that is not reading yet
+	const numChunks = 10000
+
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100})))
+	for i := 0; i < numChunks; i++ {
+		require.NoError(t, stream.Send(dataMsg("videoDataChunk")))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	var uploading []*apiv1.Progress
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		if p := msg.GetProgress(); p.GetStage() == apiv1.Stage_STAGE_UPLOADING {
+			uploading = append(uploading, p)
+		}
+	}
+
+	require.NotEmpty(t, uploading)
+	assert.Less(t, len(uploading), numChunks)
+	assert.Equal(t, uint64(numChunks*len("videoDataChunk")), uploading[len(uploading)-1].BytesReceived)
+}
+
+func TestExtractAudioSummary(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			require.NoError(t, os.WriteFile(in.FilePath, []byte("audioData"), 0o600))
+
+			return &audiostripper.ExtractAudioOutput{
+				FilePath: in.FilePath,
+				Media: &audiostripper.MediaInfo{
+					FormatName: "ogg",
+					Duration:   90 * time.Second,
+					Streams: []audiostripper.StreamInfo{
+						{Index: 0, Type: audiostripper.StreamTypeAudio, Codec: "opus", SampleRate: 48000, Channels: 1},
+					},
+				},
+			}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	stream, err := client.ExtractAudio(context.TODO())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 48000, Format: apiv1.AudioFormat_AUDIO_FORMAT_OPUS})))
+	require.NoError(t, stream.Send(dataMsg("videoData")))
+	require.NoError(t, stream.CloseSend())
+
+	var last *apiv1.AudioData
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		last = msg
+	}
+
+	// The summary is the last message of the stream
+	summary := last.GetSummary()
+	require.NotNil(t, summary)
+
+	checksum := sha256.Sum256([]byte("audioData"))
+
+	assert.Equal(t, uint64(len("audioData")), summary.TotalBytes)
+	assert.Equal(t, hex.EncodeToString(checksum[:]), summary.Sha256)
+	assert.Equal(t, 90*time.Second, summary.Duration.AsDuration())
+	assert.Equal(t, uint32(48000), summary.SampleRateHz)
+	assert.Equal(t, uint32(1), summary.Channels)
+	assert.Equal(t, "opus", summary.Codec)
+}
+
+func TestExtractAudioAllStreams(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			assert.True(t, in.AllStreams)
+			assert.Nil(t, in.Stream)
+
+			var out audiostripper.ExtractAudioOutput
+
+			for i, stream := range []audiostripper.StreamInfo{
+				{Index: 1, Type: audiostripper.StreamTypeAudio, Codec: "aac", Language: "eng"},
+				{Index: 2, Type: audiostripper.StreamTypeAudio, Codec: "opus", Language: "por"},
+			} {
+				path := fmt.Sprintf("%s.%d.wav", in.FilePath, i)
+				require.NoError(t, os.WriteFile(path, []byte("audio"+stream.Language), 0o600))
+
+				out.Tracks = append(out.Tracks, audiostripper.Track{Stream: stream, FilePath: path})
+			}
+			return &out, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	stream, err := client.ExtractAudio(context.TODO())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{
+		SampleRateHz: 48000,
+		Stream:       &apiv1.ExtractOptions_AllStreams{AllStreams: true},
+	})))
+	require.NoError(t, stream.Send(dataMsg("videoData")))
+	require.NoError(t, stream.CloseSend())
+
+	var (
+		tracks []*apiv1.Track
+		audio  = map[uint32]string{}
+		sizes  = map[uint32]uint64{}
+	)
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		switch payload := msg.Payload.(type) {
+		case *apiv1.AudioData_Track:
+			tracks = append(tracks, payload.Track)
+		case *apiv1.AudioData_Data:
+			require.NotEmpty(t, tracks, "audio must follow a track header")
+			audio[tracks[len(tracks)-1].StreamIndex] += string(payload.Data)
+		case *apiv1.AudioData_Summary:
+			sizes[tracks[len(tracks)-1].StreamIndex] = payload.Summary.TotalBytes
+		}
+	}
+
+	require.Len(t, tracks, 2)
+
+	assert.Equal(t, uint32(1), tracks[0].StreamIndex)
+	assert.Equal(t, "eng", tracks[0].Language)
+	assert.Equal(t, "aac", tracks[0].Codec)
+	assert.Equal(t, uint32(2), tracks[1].StreamIndex)
+	assert.Equal(t, "por", tracks[1].Language)
+	assert.Equal(t, "opus", tracks[1].Codec)
+
+	assert.Equal(t, map[uint32]string{1: "audioeng", 2: "audiopor"}, audio)
+	assert.Equal(t, map[uint32]uint64{1: 8, 2: 8}, sizes)
+}
+
+func TestExtractAudioUploadIntegrity(t *testing.T) {
+	video := "videoDataChunk1videoDataChunk2"
+	checksum := sha256.Sum256([]byte(video))
+	otherChecksum := sha256.Sum256([]byte("videoDataChunk1"))
+
+	testCases := []struct {
+		name         string
+		givenSha256  string
+		givenSize    uint64
+		expectedCode codes.Code
+	}{
+		{
+			name:         "matching checksum and size",
+			givenSha256:  hex.EncodeToString(checksum[:]),
+			givenSize:    uint64(len(video)),
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "checksum mismatch",
+			givenSha256:  hex.EncodeToString(otherChecksum[:]),
+			expectedCode: codes.DataLoss,
+		},
+		{
+			name:         "truncated upload",
+			givenSize:    uint64(len(video)) + 1,
+			expectedCode: codes.DataLoss,
+		},
+		{
+			name:         "oversized upload",
+			givenSize:    uint64(len(video)) - 1,
+			expectedCode: codes.DataLoss,
+		},
+		{
+			name:         "malformed checksum",
+			givenSha256:  "not-a-checksum",
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := mockAudioStripperService{
+				ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+					assert.Equal(t, codes.OK, tc.expectedCode, "service must not be called for an unverified upload")
+					return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+				},
+			}
+
+			server, lis := makeGRPCServerHelper(t, &mockService)
+			defer server.Stop()
+
+			client := makeGRPCClientHelper(t, lis)
+
+			_, err := extractAudioHelper(t, client,
+				optionsMsg(&apiv1.ExtractOptions{
+					SampleRateHz:   44100,
+					ExpectedSha256: tc.givenSha256,
+					ExpectedSize:   tc.givenSize,
+				}),
+				dataMsg("videoDataChunk1"),
+				dataMsg("videoDataChunk2"),
+			)
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestProbeMedia(t *testing.T) {
+	var inputPath string
+
+	mockService := mockAudioStripperService{
+		ProbeMediaFunc: func(ctx context.Context, in *audiostripper.ProbeMediaInput) (*audiostripper.MediaInfo, error) {
+			inputPath = in.FilePath
+
+			inputFileData, err := os.ReadFile(in.FilePath)
+			require.NoError(t, err)
+			require.Equal(t, []byte("videoDataChunk1videoDataChunk2"), inputFileData)
+
+			return &audiostripper.MediaInfo{
+				FormatName: "matroska,webm",
+				Duration:   time.Minute,
+				BitRate:    1205000,
+				Streams: []audiostripper.StreamInfo{
+					{Index: 0, Type: audiostripper.StreamTypeVideo, Codec: "vp9"},
+					{Index: 1, Type: audiostripper.StreamTypeAudio, Codec: "opus", SampleRate: 48000, Channels: 2, Language: "eng"},
+				},
+			}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	stream, err := client.ProbeMedia(context.TODO())
+	require.NoError(t, err)
+
+	// The options header is optional when probing
+	require.NoError(t, stream.Send(dataMsg("videoDataChunk1")))
+	require.NoError(t, stream.Send(dataMsg("videoDataChunk2")))
+
+	got, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	assert.Equal(t, "matroska,webm", got.FormatName)
+	assert.Equal(t, time.Minute, got.Duration.AsDuration())
+	assert.Equal(t, uint64(1205000), got.BitRate)
+	require.Len(t, got.Streams, 2)
+
+	assert.Equal(t, apiv1.StreamType_STREAM_TYPE_VIDEO, got.Streams[0].Type)
+	assert.Equal(t, "vp9", got.Streams[0].Codec)
+
+	assert.Equal(t, uint32(1), got.Streams[1].Index)
+	assert.Equal(t, apiv1.StreamType_STREAM_TYPE_AUDIO, got.Streams[1].Type)
+	assert.Equal(t, "opus", got.Streams[1].Codec)
+	assert.Equal(t, uint32(48000), got.Streams[1].SampleRateHz)
+	assert.Equal(t, uint32(2), got.Streams[1].Channels)
+	assert.Equal(t, "eng", got.Streams[1].Language)
+
+	// The upload is removed once probed
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(inputPath)
+		return os.IsNotExist(err)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestProbeMediaErrors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		serviceErr     error
+		expectedCode   codes.Code
+		expectedReason string
+	}{
+		{
+			name:           "input rejected by ffprobe",
+			serviceErr:     &audiostripper.CmdError{Cmd: "probe", Err: errors.New("exit status 1"), Kind: audiostripper.ErrInvalidData},
+			expectedCode:   codes.InvalidArgument,
+			expectedReason: "INVALID_DATA",
+		},
+		{
+			name:           "unsupported codec",
+			serviceErr:     &audiostripper.CmdError{Cmd: "probe", Err: errors.New("exit status 1"), Kind: audiostripper.ErrUnsupportedCodec},
+			expectedCode:   codes.FailedPrecondition,
+			expectedReason: "UNSUPPORTED_CODEC",
+		},
+		{
+			name:         "unknown failure",
+			serviceErr:   errors.New("could not parse probe output"),
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := mockAudioStripperService{
+				ProbeMediaFunc: func(ctx context.Context, in *audiostripper.ProbeMediaInput) (*audiostripper.MediaInfo, error) {
+					return nil, tc.serviceErr
+				},
+			}
+
+			server, lis := makeGRPCServerHelper(t, &mockService)
+			defer server.Stop()
+
+			client := makeGRPCClientHelper(t, lis)
+
+			stream, err := client.ProbeMedia(context.TODO())
+			require.NoError(t, err)
+
+			require.NoError(t, stream.Send(dataMsg("notAVideo")))
+
+			_, err = stream.CloseAndRecv()
+			require.Equal(t, tc.expectedCode, status.Code(err))
+
+			// Failures of ffprobe are detailed as those of ffmpeg
+			st, _ := status.FromError(err)
+			if tc.expectedReason == "" {
+				assert.Empty(t, st.Details())
+				return
+			}
+
+			require.Len(t, st.Details(), 1)
+			info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedReason, info.Reason)
+			assert.Equal(t, "probe", info.Metadata["command"])
+		})
+	}
+}
+
+func TestExtractAudioTimeRange(t *testing.T) {
+	testCases := []struct {
+		name          string
+		given         *apiv1.ExtractOptions
+		serviceErr    error
+		expectedStart time.Duration
+		expectedEnd   time.Duration
+		expectedCode  codes.Code
+	}{
+		{
+			name: "start offset and duration",
+			given: &apiv1.ExtractOptions{
+				StartOffset: durationpb.New(10 * time.Second),
+				End:         &apiv1.ExtractOptions_Duration{Duration: durationpb.New(5 * time.Second)},
+			},
+			expectedStart: 10 * time.Second,
+			expectedEnd:   15 * time.Second,
+			expectedCode:  codes.OK,
+		},
+		{
+			name: "end time",
+			given: &apiv1.ExtractOptions{
+				End: &apiv1.ExtractOptions_EndTime{EndTime: durationpb.New(time.Minute)},
+			},
+			expectedEnd:  time.Minute,
+			expectedCode: codes.OK,
+		},
+		{
+			name: "negative duration",
+			given: &apiv1.ExtractOptions{
+				End: &apiv1.ExtractOptions_Duration{Duration: durationpb.New(-time.Second)},
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "range beyond the video",
+			given: &apiv1.ExtractOptions{
+				StartOffset: durationpb.New(time.Hour),
+			},
+			serviceErr:    audiostripper.ErrOutOfRange,
+			expectedStart: time.Hour,
+			expectedCode:  codes.OutOfRange,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := mockAudioStripperService{
+				ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+					assert.Equal(t, tc.expectedStart, in.Start)
+					assert.Equal(t, tc.expectedEnd, in.End)
+
+					if tc.serviceErr != nil {
+						return nil, tc.serviceErr
+					}
+					return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+				},
+			}
+
+			server, lis := makeGRPCServerHelper(t, &mockService)
+			defer server.Stop()
+
+			client := makeGRPCClientHelper(t, lis)
+
+			tc.given.SampleRateHz = 44100
+
+			_, err := extractAudioHelper(t, client, optionsMsg(tc.given), dataMsg("videoData"))
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestExtractAudioStreamSelection(t *testing.T) {
+	testCases := []struct {
+		name         string
+		given        *apiv1.ExtractOptions
+		serviceErr   error
+		expected     *audiostripper.StreamSelector
+		expectedCode codes.Code
+	}{
+		{
+			name:         "unset",
+			given:        &apiv1.ExtractOptions{},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "by index",
+			given:        &apiv1.ExtractOptions{Stream: &apiv1.ExtractOptions_StreamIndex{StreamIndex: 0}},
+			expected:     &audiostripper.StreamSelector{Index: 0},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "by language",
+			given:        &apiv1.ExtractOptions{Stream: &apiv1.ExtractOptions_StreamLanguage{StreamLanguage: "eng"}},
+			expected:     &audiostripper.StreamSelector{Language: "eng"},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "missing stream",
+			given:        &apiv1.ExtractOptions{Stream: &apiv1.ExtractOptions_StreamIndex{StreamIndex: 3}},
+			serviceErr:   audiostripper.ErrNotFound,
+			expected:     &audiostripper.StreamSelector{Index: 3},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := mockAudioStripperService{
+				ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+					assert.Equal(t, tc.expected, in.Stream)
+
+					if tc.serviceErr != nil {
+						return nil, tc.serviceErr
+					}
+					return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+				},
+			}
+
+			server, lis := makeGRPCServerHelper(t, &mockService)
+			defer server.Stop()
+
+			client := makeGRPCClientHelper(t, lis)
+
+			tc.given.SampleRateHz = 44100
+
+			_, err := extractAudioHelper(t, client, optionsMsg(tc.given), dataMsg("videoData"))
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
 const bufSize int = 512 * 1024 // 512 KB should be enough for our tests
 
-func makeGRPCServerHelper(t *testing.T, service *mockAudioStripperService) (*grpc.Server, *bufconn.Listener) {
+// makeGRPCServerHelper starts a server on service. Uploads are written to disk, in a test work directory,
+// unless opts say otherwise.
+func TestExtractAudioCleanup(t *testing.T) {
+	workDir := t.TempDir()
+
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			assert.Equal(t, workDir, filepath.Dir(in.FilePath))
+
+			if in.SampleRate == 8000 {
+				return nil, errors.New("ffmpeg failed")
+			}
+
+			output := in.FilePath + ".wav"
+			assert.NoError(t, os.WriteFile(output, []byte("audioData"), 0o600))
+			return &audiostripper.ExtractAudioOutput{FilePath: output}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService, WithWorkDir(workDir))
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	testCases := []struct {
+		name         string
+		msgs         []*apiv1.VideoData
+		expectedCode codes.Code
+	}{
+		{
+			name:         "success",
+			msgs:         []*apiv1.VideoData{optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100}), dataMsg("videoData")},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "extraction failure",
+			msgs:         []*apiv1.VideoData{optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 8000}), dataMsg("videoData")},
+			expectedCode: codes.Internal,
+		},
+		{
+			name:         "upload failure",
+			msgs:         []*apiv1.VideoData{optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100, ExpectedSize: 20}), dataMsg("videoData")},
+			expectedCode: codes.DataLoss,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := extractAudioHelper(t, client, tc.msgs...)
+			require.Equal(t, tc.expectedCode, status.Code(err))
+
+			entries, err := os.ReadDir(workDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestExtractAudioDeadline(t *testing.T) {
+	workDir := t.TempDir()
+
+	interrupted := make(chan error, 1)
+
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			// The extraction runs until the client deadline passes
+			<-ctx.Done()
+			interrupted <- ctx.Err()
+			return nil, fmt.Errorf("extractor command interrupted: %w", ctx.Err())
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService, WithWorkDir(workDir))
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	stream, err := client.ExtractAudio(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100})))
+	require.NoError(t, stream.Send(dataMsg("videoData")))
+	require.NoError(t, stream.CloseSend())
+
+	// Progress is sent until the deadline passes
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	// The server side sees either its own deadline pass or the client reset the stream, whichever comes first
+	err = <-interrupted
+	assert.True(t, errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled), err)
+
+	// The uploaded video is removed once the handler returns
+	assert.Eventually(t, func() bool {
+		entries, err := os.ReadDir(workDir)
+		return err == nil && len(entries) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestExtractAudioServiceLimits(t *testing.T) {
+	testCases := []struct {
+		name         string
+		serviceErr   error
+		expectedCode codes.Code
+	}{
+		{
+			name:         "media too long",
+			serviceErr:   fmt.Errorf("%w: the input lasts 2h0m0s, the limit is 1h0m0s", audiostripper.ErrTooLong),
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "processing time limit",
+			serviceErr:   fmt.Errorf("extractor command interrupted: %w", audiostripper.ErrTimeout),
+			expectedCode: codes.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := mockAudioStripperService{
+				ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+					return nil, tc.serviceErr
+				},
+			}
+
+			server, lis := makeGRPCServerHelper(t, &mockService)
+			defer server.Stop()
+
+			client := makeGRPCClientHelper(t, lis)
+
+			_, err := extractAudioHelper(t, client, optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100}), dataMsg("videoData"))
+			require.Equal(t, tc.expectedCode, status.Code(err))
+			assert.Contains(t, status.Convert(err).Message(), tc.serviceErr.Error())
+		})
+	}
+}
+
+func TestExtractAudioCmdFailure(t *testing.T) {
+	testCases := []struct {
+		name           string
+		kind           error
+		expectedCode   codes.Code
+		expectedReason string
+	}{
+		{
+			name:           "invalid data",
+			kind:           audiostripper.ErrInvalidData,
+			expectedCode:   codes.InvalidArgument,
+			expectedReason: "INVALID_DATA",
+		},
+		{
+			name:           "no audio stream",
+			kind:           audiostripper.ErrNoAudio,
+			expectedCode:   codes.FailedPrecondition,
+			expectedReason: "NO_AUDIO_STREAM",
+		},
+		{
+			name:           "unsupported codec",
+			kind:           audiostripper.ErrUnsupportedCodec,
+			expectedCode:   codes.FailedPrecondition,
+			expectedReason: "UNSUPPORTED_CODEC",
+		},
+		{
+			name:           "disk full",
+			kind:           audiostripper.ErrDiskFull,
+			expectedCode:   codes.ResourceExhausted,
+			expectedReason: "DISK_FULL",
+		},
+		{
+			name:           "resource limit exceeded",
+			kind:           audiostripper.ErrResourceLimit,
+			expectedCode:   codes.ResourceExhausted,
+			expectedReason: "RESOURCE_LIMIT",
+		},
+		{
+			name:           "unknown failure",
+			expectedCode:   codes.Internal,
+			expectedReason: "COMMAND_FAILED",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := mockAudioStripperService{
+				ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+					return nil, &audiostripper.CmdError{
+						Cmd:    "extractor",
+						Err:    errors.New("exit status 1"),
+						Kind:   tc.kind,
+						Stderr: "Conversion failed!",
+					}
+				},
+			}
+
+			server, lis := makeGRPCServerHelper(t, &mockService)
+			defer server.Stop()
+
+			client := makeGRPCClientHelper(t, lis)
+
+			_, err := extractAudioHelper(t, client, optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100}), dataMsg("videoData"))
+
+			st := status.Convert(err)
+			require.Equal(t, tc.expectedCode, st.Code())
+			require.Len(t, st.Details(), 1)
+
+			info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedReason, info.Reason)
+			assert.Equal(t, errorDomain, info.Domain)
+			assert.Equal(t, map[string]string{"command": "extractor", "stderr": "Conversion failed!"}, info.Metadata)
+		})
+	}
+}
+
+func makeGRPCServerHelper(t *testing.T, service *mockAudioStripperService, opts ...Option) (*grpc.Server, *bufconn.Listener) {
+	t.Helper()
+	return makeGRPCServerWithJobsHelper(t, service, jobs.NewManager(noopLogger(), service), opts...)
+}
+
+// makeGRPCServerWithJobsHelper is makeGRPCServerHelper running the jobs on jobManager.
+func makeGRPCServerWithJobsHelper(t *testing.T, service *mockAudioStripperService, jobManager *jobs.Manager, opts ...Option) (*grpc.Server, *bufconn.Listener) {
 	t.Helper()
 
 	s := grpc.NewServer()
 
-	apiv1.RegisterAudioStripperServer(s, NewGRPCServer(noopLogger(), service))
+	opts = append([]Option{WithMaxInMemorySize(0), WithWorkDir(t.TempDir())}, opts...)
+	apiv1.RegisterAudioStripperServer(s, NewGRPCServer(noopLogger(), service, jobManager, uploads.NewManager(t.TempDir(), time.Hour), opts...))
 
 	serverErrCh := make(chan error, 1)
 	serverStartedCh := make(chan struct{}, 1)
@@ -156,3 +1139,37 @@ func makeGRPCClientHelper(t *testing.T, lis *bufconn.Listener) apiv1.AudioStripp
 func noopLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, nil))
 }
+
+// extractAudioHelper sends the given messages on an ExtractAudio stream and
+// returns the received audio data along with the stream error, nil on success.
+func extractAudioHelper(t *testing.T, client apiv1.AudioStripperClient, msgs ...*apiv1.VideoData) ([]byte, error) {
+	t.Helper()
+
+	stream, err := client.ExtractAudio(context.TODO())
+	require.NoError(t, err)
+
+	for _, msg := range msgs {
+		require.NoError(t, stream.Send(msg))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	var audio []byte
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return audio, nil
+		}
+		if err != nil {
+			return audio, err
+		}
+		audio = append(audio, msg.GetData()...)
+	}
+}
+
+func optionsMsg(opts *apiv1.ExtractOptions) *apiv1.VideoData {
+	return &apiv1.VideoData{Payload: &apiv1.VideoData_Options{Options: opts}}
+}
+
+func dataMsg(data string) *apiv1.VideoData {
+	return &apiv1.VideoData{Payload: &apiv1.VideoData_Data{Data: []byte(data)}}
+}
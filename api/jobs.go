@@ -0,0 +1,215 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/app/jobs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var jobStates = map[jobs.State]apiv1.JobState{
+	jobs.StateQueued:    apiv1.JobState_JOB_STATE_QUEUED,
+	jobs.StateRunning:   apiv1.JobState_JOB_STATE_RUNNING,
+	jobs.StateSucceeded: apiv1.JobState_JOB_STATE_SUCCEEDED,
+	jobs.StateFailed:    apiv1.JobState_JOB_STATE_FAILED,
+	jobs.StateCanceled:  apiv1.JobState_JOB_STATE_CANCELED,
+}
+
+func (s *GRPCServer) SubmitJob(stream apiv1.AudioStripper_SubmitJobServer) error {
+	req, err := s.receiveExtractionRequest(stream, nil)
+	if err != nil {
+		return err
+	}
+
+	job, err := s.jobs.Submit(caller(stream.Context()), req.input, req.opts.ClientMetadata)
+	if err != nil {
+		os.Remove(req.input.FilePath)
+
+		if errors.Is(err, jobs.ErrQueueFull) {
+			s.logger.Warn("Job queue full, rejecting job", slog.String("error", err.Error()))
+			return s.queueFullError(stream, "jobs")
+		}
+		return status.Errorf(codes.Internal, "failed to submit job: %v", err)
+	}
+
+	if err := stream.SendAndClose(newJob(job)); err != nil {
+		return status.Errorf(codes.Internal, "failed to send job to client: %s", err)
+	}
+	return nil
+}
+
+func (s *GRPCServer) GetJob(ctx context.Context, req *apiv1.GetJobRequest) (*apiv1.Job, error) {
+	job, err := s.callerJob(ctx, req.JobId)
+	if err != nil {
+		return nil, err
+	}
+	return newJob(job), nil
+}
+
+func (s *GRPCServer) WatchJob(req *apiv1.GetJobRequest, stream apiv1.AudioStripper_WatchJobServer) error {
+	if _, err := s.callerJob(stream.Context(), req.JobId); err != nil {
+		return err
+	}
+
+	err := s.jobs.Watch(stream.Context(), req.JobId, func(job *jobs.Job) error {
+		if err := stream.Send(newJob(job)); err != nil {
+			return status.Errorf(codes.Internal, "failed to send job to client: %s", err)
+		}
+		return nil
+	})
+
+	// The client went away or gave up
+	if ctxErr := stream.Context().Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+		return status.FromContextError(ctxErr).Err()
+	}
+	return jobError(err)
+}
+
+func (s *GRPCServer) CancelJob(ctx context.Context, req *apiv1.CancelJobRequest) (*apiv1.Job, error) {
+	if _, err := s.callerJob(ctx, req.JobId); err != nil {
+		return nil, err
+	}
+
+	job, err := s.jobs.Cancel(req.JobId)
+	if err != nil {
+		return nil, jobError(err)
+	}
+	return newJob(job), nil
+}
+
+func (s *GRPCServer) ListJobs(ctx context.Context, req *apiv1.ListJobsRequest) (*apiv1.ListJobsResponse, error) {
+	var resp apiv1.ListJobsResponse
+
+	owner := caller(ctx)
+
+	for _, job := range s.jobs.List() {
+		if job.Owner != owner {
+			continue
+		}
+		if req.State != apiv1.JobState_JOB_STATE_UNSPECIFIED && jobStates[job.State] != req.State {
+			continue
+		}
+		resp.Jobs = append(resp.Jobs, newJob(job))
+	}
+	return &resp, nil
+}
+
+func (s *GRPCServer) DownloadResult(req *apiv1.DownloadResultRequest, stream apiv1.AudioStripper_DownloadResultServer) error {
+	job, err := s.callerJob(stream.Context(), req.JobId)
+	if err != nil {
+		return err
+	}
+
+	if job.State != jobs.StateSucceeded {
+		return status.Errorf(codes.FailedPrecondition, "job %s is %s, only the result of a succeeded job can be downloaded", job.ID, job.State)
+	}
+
+	if job.Output == nil {
+		return status.Errorf(codes.NotFound, "result of job %s expired", job.ID)
+	}
+
+	for _, path := range resultFiles(job.Output) {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return status.Errorf(codes.NotFound, "result of job %s is no longer available", job.ID)
+		}
+	}
+
+	if req.Offset == 0 && req.Length == 0 {
+		return s.sendOutput(stream, job.Output)
+	}
+
+	// A range is taken from a single audio file
+	path, media := job.Output.FilePath, job.Output.Media
+
+	if len(job.Output.Tracks) > 0 {
+		if int(req.Track) >= len(job.Output.Tracks) {
+			return status.Errorf(codes.InvalidArgument, "track %d requested, job %s has %d", req.Track, job.ID, len(job.Output.Tracks))
+		}
+		path, media = job.Output.Tracks[req.Track].FilePath, job.Output.Tracks[req.Track].Media
+	} else if req.Track > 0 {
+		return status.Errorf(codes.InvalidArgument, "track %d requested, job %s has a single output", req.Track, job.ID)
+	}
+	return s.sendAudioRange(stream, path, media, req.Offset, req.Length)
+}
+
+// callerJob returns the job with the given ID if it belongs to the client of the RPC.
+// The jobs of other clients are reported as not found, not to tell they exist.
+func (s *GRPCServer) callerJob(ctx context.Context, id string) (*jobs.Job, error) {
+	job, err := s.jobs.Get(id)
+	if err != nil {
+		return nil, jobError(err)
+	}
+
+	if job.Owner != caller(ctx) {
+		return nil, jobError(fmt.Errorf("%w: %s", jobs.ErrNotFound, id))
+	}
+	return job, nil
+}
+
+// jobError converts an error of the job manager into a gRPC status.
+func jobError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, jobs.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, jobs.ErrFinished):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		// Errors already converted, e.g. by a failed send
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.Internal, "job failed: %v", err)
+	}
+}
+
+// resultFiles returns the audio files extracted for output.
+func resultFiles(output *audiostripper.ExtractAudioOutput) []string {
+	if len(output.Tracks) == 0 {
+		return []string{output.FilePath}
+	}
+
+	files := make([]string, 0, len(output.Tracks))
+	for _, track := range output.Tracks {
+		files = append(files, track.FilePath)
+	}
+	return files
+}
+
+// newJob converts a job of the job manager into its API representation.
+func newJob(job *jobs.Job) *apiv1.Job {
+	out := apiv1.Job{
+		Id:             job.ID,
+		State:          jobStates[job.State],
+		ClientMetadata: job.Metadata,
+		CreatedAt:      timestamppb.New(job.CreatedAt),
+		UpdatedAt:      timestamppb.New(job.UpdatedAt),
+	}
+
+	if job.State == jobs.StateRunning {
+		out.Progress = &apiv1.Progress{
+			Stage:   apiv1.Stage_STAGE_EXTRACTING,
+			OutTime: durationpb.New(job.Progress.OutTime),
+			Percent: float32(job.Progress.Percent()),
+		}
+	}
+
+	if !job.ExpiresAt.IsZero() {
+		out.ResultExpiresAt = timestamppb.New(job.ExpiresAt)
+	}
+
+	if job.Err != nil {
+		out.Error = job.Err.Error()
+	}
+	return &out
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestJobs(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			assert.Equal(t, 44100, in.SampleRate)
+
+			output := in.FilePath + ".wav"
+			assert.NoError(t, os.WriteFile(output, []byte("audioData"), 0o600))
+			return &audiostripper.ExtractAudioOutput{FilePath: output}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	job := submitJobHelper(t, client, optionsMsg(&apiv1.ExtractOptions{
+		SampleRateHz:   44100,
+		ClientMetadata: map[string]string{"request_id": "1"},
+	}), dataMsg("videoData"))
+
+	assert.NotEmpty(t, job.Id)
+	assert.Equal(t, map[string]string{"request_id": "1"}, job.ClientMetadata)
+
+	// Watch the job until it finishes
+	watch, err := client.WatchJob(context.TODO(), &apiv1.GetJobRequest{JobId: job.Id})
+	require.NoError(t, err)
+
+	var last *apiv1.Job
+	for {
+		msg, err := watch.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		last = msg
+	}
+	require.Equal(t, apiv1.JobState_JOB_STATE_SUCCEEDED, last.State)
+
+	got, err := client.GetJob(context.TODO(), &apiv1.GetJobRequest{JobId: job.Id})
+	require.NoError(t, err)
+	assert.Equal(t, apiv1.JobState_JOB_STATE_SUCCEEDED, got.State)
+
+	list, err := client.ListJobs(context.TODO(), &apiv1.ListJobsRequest{State: apiv1.JobState_JOB_STATE_SUCCEEDED})
+	require.NoError(t, err)
+	require.Len(t, list.Jobs, 1)
+	assert.Equal(t, job.Id, list.Jobs[0].Id)
+
+	list, err = client.ListJobs(context.TODO(), &apiv1.ListJobsRequest{State: apiv1.JobState_JOB_STATE_RUNNING})
+	require.NoError(t, err)
+	assert.Empty(t, list.Jobs)
+
+	_, err = client.CancelJob(context.TODO(), &apiv1.CancelJobRequest{JobId: job.Id})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	audio, err := downloadResultHelper(t, client, &apiv1.DownloadResultRequest{JobId: job.Id})
+	require.NoError(t, err)
+	assert.Equal(t, "audioData", string(audio))
+
+	// The result is kept until it expires, an interrupted download resumes from an offset
+	audio, err = downloadResultHelper(t, client, &apiv1.DownloadResultRequest{JobId: job.Id, Offset: 5})
+	require.NoError(t, err)
+	assert.Equal(t, "Data", string(audio))
+
+	audio, err = downloadResultHelper(t, client, &apiv1.DownloadResultRequest{JobId: job.Id, Offset: 2, Length: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "dio", string(audio))
+
+	_, err = downloadResultHelper(t, client, &apiv1.DownloadResultRequest{JobId: job.Id, Offset: 10})
+	require.Equal(t, codes.OutOfRange, status.Code(err))
+
+	_, err = downloadResultHelper(t, client, &apiv1.DownloadResultRequest{JobId: job.Id, Offset: 1, Track: 1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestJobsFailed(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			return nil, errors.New("ffmpeg failed")
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	job := submitJobHelper(t, client, optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100}), dataMsg("videoData"))
+
+	assert.Eventually(t, func() bool {
+		got, err := client.GetJob(context.TODO(), &apiv1.GetJobRequest{JobId: job.Id})
+		return err == nil && got.State == apiv1.JobState_JOB_STATE_FAILED && got.Error == "ffmpeg failed"
+	}, time.Second, 10*time.Millisecond)
+
+	_, err := downloadResultHelper(t, client, &apiv1.DownloadResultRequest{JobId: job.Id})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestJobsInvalidRequests(t *testing.T) {
+	server, lis := makeGRPCServerHelper(t, &mockAudioStripperService{})
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	t.Run("submit without options", func(t *testing.T) {
+		stream, err := client.SubmitJob(context.TODO())
+		require.NoError(t, err)
+
+		require.NoError(t, stream.Send(dataMsg("videoData")))
+
+		_, err = stream.CloseAndRecv()
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		_, err := client.GetJob(context.TODO(), &apiv1.GetJobRequest{JobId: "missing"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = client.CancelJob(context.TODO(), &apiv1.CancelJobRequest{JobId: "missing"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = downloadResultHelper(t, client, &apiv1.DownloadResultRequest{JobId: "missing"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestJobsOwnership(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	aliceCtx := metadata.AppendToOutgoingContext(context.TODO(), apiKeyHeader, "alice")
+	bobCtx := metadata.AppendToOutgoingContext(context.TODO(), apiKeyHeader, "bob")
+
+	stream, err := client.SubmitJob(aliceCtx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100})))
+	require.NoError(t, stream.Send(dataMsg("videoData")))
+
+	job, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	// Other clients can't tell the job exists
+	_, err = client.GetJob(bobCtx, &apiv1.GetJobRequest{JobId: job.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetJob(context.TODO(), &apiv1.GetJobRequest{JobId: job.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	watch, err := client.WatchJob(bobCtx, &apiv1.GetJobRequest{JobId: job.Id})
+	require.NoError(t, err)
+	_, err = watch.Recv()
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	download, err := client.DownloadResult(bobCtx, &apiv1.DownloadResultRequest{JobId: job.Id})
+	require.NoError(t, err)
+	_, err = download.Recv()
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	list, err := client.ListJobs(bobCtx, &apiv1.ListJobsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, list.Jobs)
+
+	_, err = client.CancelJob(bobCtx, &apiv1.CancelJobRequest{JobId: job.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// The client that submitted the job keeps full access
+	list, err = client.ListJobs(aliceCtx, &apiv1.ListJobsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Jobs, 1)
+	assert.Equal(t, job.Id, list.Jobs[0].Id)
+
+	got, err := client.CancelJob(aliceCtx, &apiv1.CancelJobRequest{JobId: job.Id})
+	require.NoError(t, err)
+	assert.Equal(t, apiv1.JobState_JOB_STATE_CANCELED, got.State)
+}
+
+func submitJobHelper(t *testing.T, client apiv1.AudioStripperClient, msgs ...*apiv1.VideoData) *apiv1.Job {
+	t.Helper()
+
+	stream, err := client.SubmitJob(context.TODO())
+	require.NoError(t, err)
+
+	for _, msg := range msgs {
+		require.NoError(t, stream.Send(msg))
+	}
+
+	job, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	return job
+}
+
+func downloadResultHelper(t *testing.T, client apiv1.AudioStripperClient, req *apiv1.DownloadResultRequest) ([]byte, error) {
+	t.Helper()
+
+	stream, err := client.DownloadResult(context.TODO(), req)
+	require.NoError(t, err)
+
+	var audio []byte
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return audio, nil
+		}
+		if err != nil {
+			return audio, err
+		}
+		audio = append(audio, msg.GetData()...)
+	}
+}
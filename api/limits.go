@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyHeader is the metadata key clients send their API key in.
+const apiKeyHeader = "x-api-key"
+
+// WithMaxUploadSize limits the size of the videos clients upload, in bytes. Zero, the default, means no limit.
+func WithMaxUploadSize(size uint64) Option {
+	return func(s *GRPCServer) {
+		s.maxUploadSize = size
+	}
+}
+
+// WithAPIKeyMaxUploadSizes limits the size of the videos uploaded by the clients sending the given API keys,
+// in place of the limit set by WithMaxUploadSize. Zero means no limit.
+func WithAPIKeyMaxUploadSizes(sizes map[string]uint64) Option {
+	return func(s *GRPCServer) {
+		s.apiKeyMaxUploadSizes = sizes
+	}
+}
+
+// uploadLimit returns the maximum upload size of the client of the RPC, zero when unlimited.
+func (s *GRPCServer) uploadLimit(ctx context.Context) uint64 {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, key := range md.Get(apiKeyHeader) {
+		if size, ok := s.apiKeyMaxUploadSizes[key]; ok {
+			return size
+		}
+	}
+	return s.maxUploadSize
+}
+
+// caller returns the identity of the client of the RPC, the SHA-256 of the API key it sends,
+// empty for clients sending none. Jobs and upload sessions belong to the caller that created them,
+// and are recorded with this identity rather than the key itself.
+func caller(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	keys := md.Get(apiKeyHeader)
+	if len(keys) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(keys[0]))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkUploadLimit fails with RESOURCE_EXHAUSTED when size exceeds limit, zero meaning no limit.
+func checkUploadLimit(size, limit uint64) error {
+	if limit > 0 && size > limit {
+		return status.Errorf(codes.ResourceExhausted, "upload exceeds the maximum size of %d bytes", limit)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+)
+
+func TestUploadLimit(t *testing.T) {
+	workDir := t.TempDir()
+
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+		StreamAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput, r io.Reader, w io.Writer) (*audiostripper.MediaInfo, error) {
+			_, err := io.Copy(w, r)
+			return &audiostripper.MediaInfo{}, err
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService,
+		WithMaxUploadSize(10),
+		WithAPIKeyMaxUploadSizes(map[string]uint64{"trusted": 100}),
+		WithWorkDir(workDir),
+	)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	testCases := []struct {
+		name string
+		opts *apiv1.ExtractOptions
+	}{
+		{
+			name: "upload",
+			opts: &apiv1.ExtractOptions{SampleRateHz: 44100},
+		},
+		{
+			name: "announced size",
+			opts: &apiv1.ExtractOptions{SampleRateHz: 44100, ExpectedSize: 30},
+		},
+		{
+			name: "streaming",
+			opts: &apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := extractAudioHelper(t, client, optionsMsg(tc.opts), dataMsg("videoDataChunk1"), dataMsg("videoDataChunk2"))
+			require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+			// The partial upload is removed
+			entries, err := os.ReadDir(workDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+
+	t.Run("api key", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyHeader, "trusted")
+
+		stream, err := client.ExtractAudio(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100})))
+		require.NoError(t, stream.Send(dataMsg("videoDataChunk1videoDataChunk2")))
+		require.NoError(t, stream.CloseSend())
+
+		var audio []byte
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			audio = append(audio, msg.GetData()...)
+		}
+		assert.Equal(t, "videoDataChunk1videoDataChunk2", string(audio))
+	})
+
+	t.Run("upload session", func(t *testing.T) {
+		_, err := client.CreateUploadSession(context.TODO(), &apiv1.CreateUploadSessionRequest{ExpectedSize: 30})
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		session, err := client.CreateUploadSession(context.TODO(), &apiv1.CreateUploadSessionRequest{})
+		require.NoError(t, err)
+
+		_, err = uploadChunksHelper(t, client, &apiv1.UploadChunk{SessionId: session.SessionId, Data: []byte("videoDataChunk1")})
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		// The session is discarded
+		_, err = client.GetUploadSession(context.TODO(), &apiv1.GetUploadSessionRequest{SessionId: session.SessionId})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
@@ -9,6 +9,8 @@ package proto_v1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -20,33 +22,1599 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Codec and container of the extracted audio.
+type AudioFormat int32
+
+const (
+	AudioFormat_AUDIO_FORMAT_UNSPECIFIED AudioFormat = 0 // Defaults to WAV.
+	AudioFormat_AUDIO_FORMAT_WAV         AudioFormat = 1 // PCM signed 16-bit little-endian in a WAV container.
+	AudioFormat_AUDIO_FORMAT_FLAC        AudioFormat = 2 // FLAC in its native container.
+	AudioFormat_AUDIO_FORMAT_MP3         AudioFormat = 3 // MPEG-1 Layer III.
+	AudioFormat_AUDIO_FORMAT_OPUS        AudioFormat = 4 // Opus in an Ogg container.
+	AudioFormat_AUDIO_FORMAT_AAC         AudioFormat = 5 // AAC in an MPEG-4 (m4a) container.
+)
+
+// Enum value maps for AudioFormat.
+var (
+	AudioFormat_name = map[int32]string{
+		0: "AUDIO_FORMAT_UNSPECIFIED",
+		1: "AUDIO_FORMAT_WAV",
+		2: "AUDIO_FORMAT_FLAC",
+		3: "AUDIO_FORMAT_MP3",
+		4: "AUDIO_FORMAT_OPUS",
+		5: "AUDIO_FORMAT_AAC",
+	}
+	AudioFormat_value = map[string]int32{
+		"AUDIO_FORMAT_UNSPECIFIED": 0,
+		"AUDIO_FORMAT_WAV":         1,
+		"AUDIO_FORMAT_FLAC":        2,
+		"AUDIO_FORMAT_MP3":         3,
+		"AUDIO_FORMAT_OPUS":        4,
+		"AUDIO_FORMAT_AAC":         5,
+	}
+)
+
+func (x AudioFormat) Enum() *AudioFormat {
+	p := new(AudioFormat)
+	*p = x
+	return p
+}
+
+func (x AudioFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AudioFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes[0].Descriptor()
+}
+
+func (AudioFormat) Type() protoreflect.EnumType {
+	return &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes[0]
+}
+
+func (x AudioFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AudioFormat.Descriptor instead.
+func (AudioFormat) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{0}
+}
+
+// Channel layout of the extracted audio.
+type ChannelLayout int32
+
+const (
+	ChannelLayout_CHANNEL_LAYOUT_UNSPECIFIED  ChannelLayout = 0 // Defaults to stereo.
+	ChannelLayout_CHANNEL_LAYOUT_MONO         ChannelLayout = 1 // Downmix to a single channel.
+	ChannelLayout_CHANNEL_LAYOUT_STEREO       ChannelLayout = 2 // Downmix or upmix to two channels.
+	ChannelLayout_CHANNEL_LAYOUT_SURROUND_5_1 ChannelLayout = 3 // Six channels in a 5.1 layout. Not supported by MP3.
+	ChannelLayout_CHANNEL_LAYOUT_SOURCE       ChannelLayout = 4 // Keep the channel layout of the source audio.
+)
+
+// Enum value maps for ChannelLayout.
+var (
+	ChannelLayout_name = map[int32]string{
+		0: "CHANNEL_LAYOUT_UNSPECIFIED",
+		1: "CHANNEL_LAYOUT_MONO",
+		2: "CHANNEL_LAYOUT_STEREO",
+		3: "CHANNEL_LAYOUT_SURROUND_5_1",
+		4: "CHANNEL_LAYOUT_SOURCE",
+	}
+	ChannelLayout_value = map[string]int32{
+		"CHANNEL_LAYOUT_UNSPECIFIED":  0,
+		"CHANNEL_LAYOUT_MONO":         1,
+		"CHANNEL_LAYOUT_STEREO":       2,
+		"CHANNEL_LAYOUT_SURROUND_5_1": 3,
+		"CHANNEL_LAYOUT_SOURCE":       4,
+	}
+)
+
+func (x ChannelLayout) Enum() *ChannelLayout {
+	p := new(ChannelLayout)
+	*p = x
+	return p
+}
+
+func (x ChannelLayout) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChannelLayout) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes[1].Descriptor()
+}
+
+func (ChannelLayout) Type() protoreflect.EnumType {
+	return &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes[1]
+}
+
+func (x ChannelLayout) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChannelLayout.Descriptor instead.
+func (ChannelLayout) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{1}
+}
+
+// Stage of an extraction.
+type Stage int32
+
+const (
+	Stage_STAGE_UNSPECIFIED Stage = 0
+	Stage_STAGE_UPLOADING   Stage = 1 // The server is receiving the video.
+	Stage_STAGE_EXTRACTING  Stage = 2 // The audio is being extracted.
+	Stage_STAGE_SENDING     Stage = 3 // The server is sending the audio back.
+)
+
+// Enum value maps for Stage.
+var (
+	Stage_name = map[int32]string{
+		0: "STAGE_UNSPECIFIED",
+		1: "STAGE_UPLOADING",
+		2: "STAGE_EXTRACTING",
+		3: "STAGE_SENDING",
+	}
+	Stage_value = map[string]int32{
+		"STAGE_UNSPECIFIED": 0,
+		"STAGE_UPLOADING":   1,
+		"STAGE_EXTRACTING":  2,
+		"STAGE_SENDING":     3,
+	}
+)
+
+func (x Stage) Enum() *Stage {
+	p := new(Stage)
+	*p = x
+	return p
+}
+
+func (x Stage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Stage) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes[2].Descriptor()
+}
+
+func (Stage) Type() protoreflect.EnumType {
+	return &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes[2]
+}
+
+func (x Stage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Stage.Descriptor instead.
+func (Stage) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{2}
+}
+
+// Type of a media stream.
+type StreamType int32
+
+const (
+	StreamType_STREAM_TYPE_UNSPECIFIED StreamType = 0
+	StreamType_STREAM_TYPE_AUDIO       StreamType = 1
+	StreamType_STREAM_TYPE_VIDEO       StreamType = 2
+	StreamType_STREAM_TYPE_SUBTITLE    StreamType = 3
+	StreamType_STREAM_TYPE_DATA        StreamType = 4
+	StreamType_STREAM_TYPE_ATTACHMENT  StreamType = 5
+)
+
+// Enum value maps for StreamType.
+var (
+	StreamType_name = map[int32]string{
+		0: "STREAM_TYPE_UNSPECIFIED",
+		1: "STREAM_TYPE_AUDIO",
+		2: "STREAM_TYPE_VIDEO",
+		3: "STREAM_TYPE_SUBTITLE",
+		4: "STREAM_TYPE_DATA",
+		5: "STREAM_TYPE_ATTACHMENT",
+	}
+	StreamType_value = map[string]int32{
+		"STREAM_TYPE_UNSPECIFIED": 0,
+		"STREAM_TYPE_AUDIO":       1,
+		"STREAM_TYPE_VIDEO":       2,
+		"STREAM_TYPE_SUBTITLE":    3,
+		"STREAM_TYPE_DATA":        4,
+		"STREAM_TYPE_ATTACHMENT":  5,
+	}
+)
+
+func (x StreamType) Enum() *StreamType {
+	p := new(StreamType)
+	*p = x
+	return p
+}
+
+func (x StreamType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StreamType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes[3].Descriptor()
+}
+
+func (StreamType) Type() protoreflect.EnumType {
+	return &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes[3]
+}
+
+func (x StreamType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StreamType.Descriptor instead.
+func (StreamType) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{3}
+}
+
+// State of an extraction job.
+type JobState int32
+
+const (
+	JobState_JOB_STATE_UNSPECIFIED JobState = 0
+	JobState_JOB_STATE_QUEUED      JobState = 1
+	JobState_JOB_STATE_RUNNING     JobState = 2
+	JobState_JOB_STATE_SUCCEEDED   JobState = 3 // The result can be downloaded with DownloadResult.
+	JobState_JOB_STATE_FAILED      JobState = 4
+	JobState_JOB_STATE_CANCELED    JobState = 5
+)
+
+// Enum value maps for JobState.
+var (
+	JobState_name = map[int32]string{
+		0: "JOB_STATE_UNSPECIFIED",
+		1: "JOB_STATE_QUEUED",
+		2: "JOB_STATE_RUNNING",
+		3: "JOB_STATE_SUCCEEDED",
+		4: "JOB_STATE_FAILED",
+		5: "JOB_STATE_CANCELED",
+	}
+	JobState_value = map[string]int32{
+		"JOB_STATE_UNSPECIFIED": 0,
+		"JOB_STATE_QUEUED":      1,
+		"JOB_STATE_RUNNING":     2,
+		"JOB_STATE_SUCCEEDED":   3,
+		"JOB_STATE_FAILED":      4,
+		"JOB_STATE_CANCELED":    5,
+	}
+)
+
+func (x JobState) Enum() *JobState {
+	p := new(JobState)
+	*p = x
+	return p
+}
+
+func (x JobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes[4].Descriptor()
+}
+
+func (JobState) Type() protoreflect.EnumType {
+	return &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes[4]
+}
+
+func (x JobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobState.Descriptor instead.
+func (JobState) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{4}
+}
+
+// Options for an extraction. Sent in the first message of an ExtractAudio stream.
+type ExtractOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Format AudioFormat `protobuf:"varint,1,opt,name=format,proto3,enum=AudioFormat" json:"format,omitempty"`
+	// Sample rate of the extracted audio in Hz. Required.
+	// One of 8000, 11025, 12000, 16000, 22050, 24000, 32000, 44100, 48000, 88200 or 96000.
+	SampleRateHz uint32        `protobuf:"varint,2,opt,name=sample_rate_hz,json=sampleRateHz,proto3" json:"sample_rate_hz,omitempty"`
+	Channels     ChannelLayout `protobuf:"varint,3,opt,name=channels,proto3,enum=ChannelLayout" json:"channels,omitempty"`
+	// Free-form metadata identifying the client request, e.g. a correlation ID.
+	// It is not interpreted by the server, only logged.
+	ClientMetadata map[string]string `protobuf:"bytes,4,rep,name=client_metadata,json=clientMetadata,proto3" json:"client_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Hex-encoded SHA-256 of the video. When set, the server verifies the upload
+	// and fails with DATA_LOSS before extracting the audio if it doesn't match.
+	ExpectedSha256 string `protobuf:"bytes,5,opt,name=expected_sha256,json=expectedSha256,proto3" json:"expected_sha256,omitempty"`
+	// Size of the video in bytes. When set, the server fails with DATA_LOSS
+	// if it receives a different number of bytes.
+	ExpectedSize uint64 `protobuf:"varint,6,opt,name=expected_size,json=expectedSize,proto3" json:"expected_size,omitempty"`
+	// Position in the video the extracted audio starts at.
+	StartOffset *durationpb.Duration `protobuf:"bytes,7,opt,name=start_offset,json=startOffset,proto3" json:"start_offset,omitempty"`
+	// Where the extracted audio ends, either as a duration from start_offset or as a
+	// position in the video. The audio is extracted until the end of the video when unset.
+	// Ranges that fall outside the video are rejected with OUT_OF_RANGE.
+	//
+	// Types that are assignable to End:
+	//	*ExtractOptions_Duration
+	//	*ExtractOptions_EndTime
+	End isExtractOptions_End `protobuf_oneof:"end"`
+	// Audio stream of the video to extract, when the video has several. ffmpeg picks
+	// one by its own heuristics when unset. Selecting a stream the video doesn't have
+	// fails with NOT_FOUND.
+	//
+	// Types that are assignable to Stream:
+	//	*ExtractOptions_StreamIndex
+	//	*ExtractOptions_StreamLanguage
+	//	*ExtractOptions_AllStreams
+	Stream isExtractOptions_Stream `protobuf_oneof:"stream"`
+	// Take the video from a completed upload session instead of the following messages,
+	// which must not carry any data. The session is consumed.
+	UploadSessionId string `protobuf:"bytes,13,opt,name=upload_session_id,json=uploadSessionId,proto3" json:"upload_session_id,omitempty"`
+	// Pipe the video into ffmpeg as it is received and send the audio back as it is
+	// extracted, instead of waiting for the whole upload. Only applies to ExtractAudio.
+	// The server falls back to extracting from the complete upload when the video needs
+	// seeking, e.g. an MP4 file with its index at the end, when the extraction needs the
+	// video to be probed first: trimmed, selecting streams or from an upload session, or
+	// when expected_size or expected_sha256 are set, the upload being verified first.
+	// A streamed extraction reports no UPLOADING and SENDING progress. Clients may still send
+	// the whole video before reading the audio: the upload isn't paced by ffmpeg.
+	Streaming bool `protobuf:"varint,14,opt,name=streaming,proto3" json:"streaming,omitempty"`
+}
+
+func (x *ExtractOptions) Reset() {
+	*x = ExtractOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtractOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractOptions) ProtoMessage() {}
+
+func (x *ExtractOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractOptions.ProtoReflect.Descriptor instead.
+func (*ExtractOptions) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExtractOptions) GetFormat() AudioFormat {
+	if x != nil {
+		return x.Format
+	}
+	return AudioFormat_AUDIO_FORMAT_UNSPECIFIED
+}
+
+func (x *ExtractOptions) GetSampleRateHz() uint32 {
+	if x != nil {
+		return x.SampleRateHz
+	}
+	return 0
+}
+
+func (x *ExtractOptions) GetChannels() ChannelLayout {
+	if x != nil {
+		return x.Channels
+	}
+	return ChannelLayout_CHANNEL_LAYOUT_UNSPECIFIED
+}
+
+func (x *ExtractOptions) GetClientMetadata() map[string]string {
+	if x != nil {
+		return x.ClientMetadata
+	}
+	return nil
+}
+
+func (x *ExtractOptions) GetExpectedSha256() string {
+	if x != nil {
+		return x.ExpectedSha256
+	}
+	return ""
+}
+
+func (x *ExtractOptions) GetExpectedSize() uint64 {
+	if x != nil {
+		return x.ExpectedSize
+	}
+	return 0
+}
+
+func (x *ExtractOptions) GetStartOffset() *durationpb.Duration {
+	if x != nil {
+		return x.StartOffset
+	}
+	return nil
+}
+
+func (m *ExtractOptions) GetEnd() isExtractOptions_End {
+	if m != nil {
+		return m.End
+	}
+	return nil
+}
+
+func (x *ExtractOptions) GetDuration() *durationpb.Duration {
+	if x, ok := x.GetEnd().(*ExtractOptions_Duration); ok {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *ExtractOptions) GetEndTime() *durationpb.Duration {
+	if x, ok := x.GetEnd().(*ExtractOptions_EndTime); ok {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (m *ExtractOptions) GetStream() isExtractOptions_Stream {
+	if m != nil {
+		return m.Stream
+	}
+	return nil
+}
+
+func (x *ExtractOptions) GetStreamIndex() uint32 {
+	if x, ok := x.GetStream().(*ExtractOptions_StreamIndex); ok {
+		return x.StreamIndex
+	}
+	return 0
+}
+
+func (x *ExtractOptions) GetStreamLanguage() string {
+	if x, ok := x.GetStream().(*ExtractOptions_StreamLanguage); ok {
+		return x.StreamLanguage
+	}
+	return ""
+}
+
+func (x *ExtractOptions) GetAllStreams() bool {
+	if x, ok := x.GetStream().(*ExtractOptions_AllStreams); ok {
+		return x.AllStreams
+	}
+	return false
+}
+
+func (x *ExtractOptions) GetUploadSessionId() string {
+	if x != nil {
+		return x.UploadSessionId
+	}
+	return ""
+}
+
+func (x *ExtractOptions) GetStreaming() bool {
+	if x != nil {
+		return x.Streaming
+	}
+	return false
+}
+
+type isExtractOptions_End interface {
+	isExtractOptions_End()
+}
+
+type ExtractOptions_Duration struct {
+	Duration *durationpb.Duration `protobuf:"bytes,8,opt,name=duration,proto3,oneof"`
+}
+
+type ExtractOptions_EndTime struct {
+	EndTime *durationpb.Duration `protobuf:"bytes,9,opt,name=end_time,json=endTime,proto3,oneof"`
+}
+
+func (*ExtractOptions_Duration) isExtractOptions_End() {}
+
+func (*ExtractOptions_EndTime) isExtractOptions_End() {}
+
+type isExtractOptions_Stream interface {
+	isExtractOptions_Stream()
+}
+
+type ExtractOptions_StreamIndex struct {
+	// Position of the stream among the audio streams of the video, starting at 0.
+	StreamIndex uint32 `protobuf:"varint,10,opt,name=stream_index,json=streamIndex,proto3,oneof"`
+}
+
+type ExtractOptions_StreamLanguage struct {
+	// Language tag of the stream, e.g. "eng". The first audio stream tagged with it is extracted.
+	StreamLanguage string `protobuf:"bytes,11,opt,name=stream_language,json=streamLanguage,proto3,oneof"`
+}
+
+type ExtractOptions_AllStreams struct {
+	// Extract every audio stream of the video into its own track. The audio of each
+	// track is preceded by a Track header and followed by its own Summary.
+	AllStreams bool `protobuf:"varint,12,opt,name=all_streams,json=allStreams,proto3,oneof"`
+}
+
+func (*ExtractOptions_StreamIndex) isExtractOptions_Stream() {}
+
+func (*ExtractOptions_StreamLanguage) isExtractOptions_Stream() {}
+
+func (*ExtractOptions_AllStreams) isExtractOptions_Stream() {}
+
 // Message to represent chunks of video data being sent to the server.
+// The first message of a stream must carry the options, every following message
+// carries data.
 type VideoData struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SampleRate string `protobuf:"bytes,1,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
-	Data       []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// Types that are assignable to Payload:
+	//	*VideoData_Options
+	//	*VideoData_Data
+	Payload isVideoData_Payload `protobuf_oneof:"payload"`
+	// Options sent alongside the first chunk by clients that predate ExtractOptions.
+	//
+	// Deprecated: Do not use.
+	SampleRate string `protobuf:"bytes,1,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	// Deprecated: Do not use.
+	Format AudioFormat `protobuf:"varint,3,opt,name=format,proto3,enum=AudioFormat" json:"format,omitempty"`
+	// Deprecated: Do not use.
+	Channels ChannelLayout `protobuf:"varint,4,opt,name=channels,proto3,enum=ChannelLayout" json:"channels,omitempty"`
+	// Deprecated: Do not use.
+	SampleRateHz uint32 `protobuf:"varint,5,opt,name=sample_rate_hz,json=sampleRateHz,proto3" json:"sample_rate_hz,omitempty"`
+}
+
+func (x *VideoData) Reset() {
+	*x = VideoData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VideoData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VideoData) ProtoMessage() {}
+
+func (x *VideoData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VideoData.ProtoReflect.Descriptor instead.
+func (*VideoData) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{1}
+}
+
+func (m *VideoData) GetPayload() isVideoData_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *VideoData) GetOptions() *ExtractOptions {
+	if x, ok := x.GetPayload().(*VideoData_Options); ok {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *VideoData) GetData() []byte {
+	if x, ok := x.GetPayload().(*VideoData_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+// Deprecated: Do not use.
+func (x *VideoData) GetSampleRate() string {
+	if x != nil {
+		return x.SampleRate
+	}
+	return ""
+}
+
+// Deprecated: Do not use.
+func (x *VideoData) GetFormat() AudioFormat {
+	if x != nil {
+		return x.Format
+	}
+	return AudioFormat_AUDIO_FORMAT_UNSPECIFIED
+}
+
+// Deprecated: Do not use.
+func (x *VideoData) GetChannels() ChannelLayout {
+	if x != nil {
+		return x.Channels
+	}
+	return ChannelLayout_CHANNEL_LAYOUT_UNSPECIFIED
+}
+
+// Deprecated: Do not use.
+func (x *VideoData) GetSampleRateHz() uint32 {
+	if x != nil {
+		return x.SampleRateHz
+	}
+	return 0
+}
+
+type isVideoData_Payload interface {
+	isVideoData_Payload()
+}
+
+type VideoData_Options struct {
+	Options *ExtractOptions `protobuf:"bytes,6,opt,name=options,proto3,oneof"`
+}
+
+type VideoData_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
+}
+
+func (*VideoData_Options) isVideoData_Payload() {}
+
+func (*VideoData_Data) isVideoData_Payload() {}
+
+// Progress of an extraction.
+type Progress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stage         Stage                `protobuf:"varint,1,opt,name=stage,proto3,enum=Stage" json:"stage,omitempty"`
+	BytesReceived uint64               `protobuf:"varint,2,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"` // Video bytes received so far.
+	OutTime       *durationpb.Duration `protobuf:"bytes,3,opt,name=out_time,json=outTime,proto3" json:"out_time,omitempty"`                    // Position of the last extracted audio frame.
+	// Completed percentage of the extraction, from 0 to 100.
+	// Zero when the duration of the video is unknown.
+	Percent float32 `protobuf:"fixed32,4,opt,name=percent,proto3" json:"percent,omitempty"`
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Progress) GetStage() Stage {
+	if x != nil {
+		return x.Stage
+	}
+	return Stage_STAGE_UNSPECIFIED
+}
+
+func (x *Progress) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *Progress) GetOutTime() *durationpb.Duration {
+	if x != nil {
+		return x.OutTime
+	}
+	return nil
+}
+
+func (x *Progress) GetPercent() float32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+// Summary of the extracted audio, as produced by ffmpeg.
+type Summary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalBytes   uint64               `protobuf:"varint,1,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"` // Number of audio bytes sent.
+	Sha256       string               `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`                            // Hex-encoded SHA-256 of the audio bytes sent.
+	Duration     *durationpb.Duration `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`                        // Duration of the audio.
+	SampleRateHz uint32               `protobuf:"varint,4,opt,name=sample_rate_hz,json=sampleRateHz,proto3" json:"sample_rate_hz,omitempty"`
+	Channels     uint32               `protobuf:"varint,5,opt,name=channels,proto3" json:"channels,omitempty"`
+	Codec        string               `protobuf:"bytes,6,opt,name=codec,proto3" json:"codec,omitempty"` // Codec name as reported by ffprobe, e.g. "pcm_s16le".
+}
+
+func (x *Summary) Reset() {
+	*x = Summary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Summary) ProtoMessage() {}
+
+func (x *Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Summary.ProtoReflect.Descriptor instead.
+func (*Summary) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Summary) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *Summary) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *Summary) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *Summary) GetSampleRateHz() uint32 {
+	if x != nil {
+		return x.SampleRateHz
+	}
+	return 0
+}
+
+func (x *Summary) GetChannels() uint32 {
+	if x != nil {
+		return x.Channels
+	}
+	return 0
+}
+
+func (x *Summary) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+// Source stream of the audio that follows, sent before the audio of each track
+// when all audio streams are extracted.
+type Track struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StreamIndex uint32 `protobuf:"varint,1,opt,name=stream_index,json=streamIndex,proto3" json:"stream_index,omitempty"` // Index of the stream in the video.
+	Language    string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`                           // Language tag of the stream, empty when untagged.
+	Codec       string `protobuf:"bytes,3,opt,name=codec,proto3" json:"codec,omitempty"`                                 // Codec of the stream in the video.
+}
+
+func (x *Track) Reset() {
+	*x = Track{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Track) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Track) ProtoMessage() {}
+
+func (x *Track) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Track.ProtoReflect.Descriptor instead.
+func (*Track) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Track) GetStreamIndex() uint32 {
+	if x != nil {
+		return x.StreamIndex
+	}
+	return 0
+}
+
+func (x *Track) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Track) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+// Message to represent chunks of audio data being sent back to the client.
+// Progress updates are interleaved with the data while the extraction runs,
+// and the last message of a successful stream is the summary.
+// When all audio streams are extracted, every track is sent in turn as a
+// Track header, its data and its summary.
+type AudioData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Payload:
+	//	*AudioData_Data
+	//	*AudioData_Progress
+	//	*AudioData_Summary
+	//	*AudioData_Track
+	Payload isAudioData_Payload `protobuf_oneof:"payload"`
+}
+
+func (x *AudioData) Reset() {
+	*x = AudioData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AudioData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioData) ProtoMessage() {}
+
+func (x *AudioData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioData.ProtoReflect.Descriptor instead.
+func (*AudioData) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{5}
+}
+
+func (m *AudioData) GetPayload() isAudioData_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *AudioData) GetData() []byte {
+	if x, ok := x.GetPayload().(*AudioData_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *AudioData) GetProgress() *Progress {
+	if x, ok := x.GetPayload().(*AudioData_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *AudioData) GetSummary() *Summary {
+	if x, ok := x.GetPayload().(*AudioData_Summary); ok {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *AudioData) GetTrack() *Track {
+	if x, ok := x.GetPayload().(*AudioData_Track); ok {
+		return x.Track
+	}
+	return nil
+}
+
+type isAudioData_Payload interface {
+	isAudioData_Payload()
+}
+
+type AudioData_Data struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3,oneof"`
+}
+
+type AudioData_Progress struct {
+	Progress *Progress `protobuf:"bytes,2,opt,name=progress,proto3,oneof"`
+}
+
+type AudioData_Summary struct {
+	Summary *Summary `protobuf:"bytes,3,opt,name=summary,proto3,oneof"`
+}
+
+type AudioData_Track struct {
+	Track *Track `protobuf:"bytes,4,opt,name=track,proto3,oneof"`
+}
+
+func (*AudioData_Data) isAudioData_Payload() {}
+
+func (*AudioData_Progress) isAudioData_Payload() {}
+
+func (*AudioData_Summary) isAudioData_Payload() {}
+
+func (*AudioData_Track) isAudioData_Payload() {}
+
+// A single stream of a media file.
+type StreamInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index        uint32     `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // Index of the stream in the file.
+	Type         StreamType `protobuf:"varint,2,opt,name=type,proto3,enum=StreamType" json:"type,omitempty"`
+	Codec        string     `protobuf:"bytes,3,opt,name=codec,proto3" json:"codec,omitempty"`
+	SampleRateHz uint32     `protobuf:"varint,4,opt,name=sample_rate_hz,json=sampleRateHz,proto3" json:"sample_rate_hz,omitempty"` // Audio streams only.
+	Channels     uint32     `protobuf:"varint,5,opt,name=channels,proto3" json:"channels,omitempty"`                               // Audio streams only.
+	Language     string     `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`                                // Language tag, e.g. "eng". Empty when untagged.
+}
+
+func (x *StreamInfo) Reset() {
+	*x = StreamInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamInfo) ProtoMessage() {}
+
+func (x *StreamInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamInfo.ProtoReflect.Descriptor instead.
+func (*StreamInfo) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StreamInfo) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *StreamInfo) GetType() StreamType {
+	if x != nil {
+		return x.Type
+	}
+	return StreamType_STREAM_TYPE_UNSPECIFIED
+}
+
+func (x *StreamInfo) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+func (x *StreamInfo) GetSampleRateHz() uint32 {
+	if x != nil {
+		return x.SampleRateHz
+	}
+	return 0
+}
+
+func (x *StreamInfo) GetChannels() uint32 {
+	if x != nil {
+		return x.Channels
+	}
+	return 0
+}
+
+func (x *StreamInfo) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+// Container and stream metadata of a media file, as reported by ffprobe.
+type MediaInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FormatName string               `protobuf:"bytes,1,opt,name=format_name,json=formatName,proto3" json:"format_name,omitempty"` // e.g. "mov,mp4,m4a,3gp,3g2,mj2".
+	Duration   *durationpb.Duration `protobuf:"bytes,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	BitRate    uint64               `protobuf:"varint,3,opt,name=bit_rate,json=bitRate,proto3" json:"bit_rate,omitempty"` // In bits per second.
+	Streams    []*StreamInfo        `protobuf:"bytes,4,rep,name=streams,proto3" json:"streams,omitempty"`
+}
+
+func (x *MediaInfo) Reset() {
+	*x = MediaInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MediaInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MediaInfo) ProtoMessage() {}
+
+func (x *MediaInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MediaInfo.ProtoReflect.Descriptor instead.
+func (*MediaInfo) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MediaInfo) GetFormatName() string {
+	if x != nil {
+		return x.FormatName
+	}
+	return ""
+}
+
+func (x *MediaInfo) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *MediaInfo) GetBitRate() uint64 {
+	if x != nil {
+		return x.BitRate
+	}
+	return 0
+}
+
+func (x *MediaInfo) GetStreams() []*StreamInfo {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+// An asynchronous extraction submitted with SubmitJob.
+type Job struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State          JobState               `protobuf:"varint,2,opt,name=state,proto3,enum=JobState" json:"state,omitempty"`
+	Progress       *Progress              `protobuf:"bytes,3,opt,name=progress,proto3" json:"progress,omitempty"`                                                                                                                           // Progress of the extraction while the job runs.
+	Error          string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`                                                                                                                                 // Why the job failed, empty otherwise.
+	ClientMetadata map[string]string      `protobuf:"bytes,5,rep,name=client_metadata,json=clientMetadata,proto3" json:"client_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // Client metadata of the submitted options.
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// When the result of a succeeded job is removed. Unset when the result is kept.
+	ResultExpiresAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=result_expires_at,json=resultExpiresAt,proto3" json:"result_expires_at,omitempty"`
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *Job) GetProgress() *Progress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *Job) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Job) GetClientMetadata() map[string]string {
+	if x != nil {
+		return x.ClientMetadata
+	}
+	return nil
+}
+
+func (x *Job) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Job) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Job) GetResultExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResultExpiresAt
+	}
+	return nil
+}
+
+type GetJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+}
+
+func (x *GetJobRequest) Reset() {
+	*x = GetJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobRequest) ProtoMessage() {}
+
+func (x *GetJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobRequest.ProtoReflect.Descriptor instead.
+func (*GetJobRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type CancelJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+}
+
+func (x *CancelJobRequest) Reset() {
+	*x = CancelJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobRequest) ProtoMessage() {}
+
+func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CancelJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type ListJobsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State JobState `protobuf:"varint,1,opt,name=state,proto3,enum=JobState" json:"state,omitempty"` // Only list jobs in this state. All jobs are listed when unspecified.
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListJobsRequest) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+type ListJobsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Jobs []*Job `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"` // Oldest first.
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListJobsResponse) GetJobs() []*Job {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// Downloads the whole result when offset and length are zero. Otherwise only the
+// requested range of a single audio file is sent as data messages, followed by the
+// Summary of the whole file so the reassembled audio can be verified.
+type DownloadResultRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId  string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Offset uint64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // Position in the audio file to start from.
+	Length uint64 `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"` // Number of bytes to send, until the end of the file when zero.
+	// Position of the track to download a range of, in the order the tracks are sent,
+	// when the job extracted all audio streams.
+	Track uint32 `protobuf:"varint,4,opt,name=track,proto3" json:"track,omitempty"`
+}
+
+func (x *DownloadResultRequest) Reset() {
+	*x = DownloadResultRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownloadResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadResultRequest) ProtoMessage() {}
+
+func (x *DownloadResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadResultRequest.ProtoReflect.Descriptor instead.
+func (*DownloadResultRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DownloadResultRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *DownloadResultRequest) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *DownloadResultRequest) GetLength() uint64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *DownloadResultRequest) GetTrack() uint32 {
+	if x != nil {
+		return x.Track
+	}
+	return 0
+}
+
+type CreateUploadSessionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Size of the video in bytes. When set, chunks beyond it are rejected with OUT_OF_RANGE
+	// and the session can't be used before all of them are received.
+	ExpectedSize uint64 `protobuf:"varint,1,opt,name=expected_size,json=expectedSize,proto3" json:"expected_size,omitempty"`
+}
+
+func (x *CreateUploadSessionRequest) Reset() {
+	*x = CreateUploadSessionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateUploadSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUploadSessionRequest) ProtoMessage() {}
+
+func (x *CreateUploadSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUploadSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateUploadSessionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateUploadSessionRequest) GetExpectedSize() uint64 {
+	if x != nil {
+		return x.ExpectedSize
+	}
+	return 0
+}
+
+type GetUploadSessionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 }
 
-func (x *VideoData) Reset() {
-	*x = VideoData{}
+func (x *GetUploadSessionRequest) Reset() {
+	*x = GetUploadSessionRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[0]
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *VideoData) String() string {
+func (x *GetUploadSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VideoData) ProtoMessage() {}
+func (*GetUploadSessionRequest) ProtoMessage() {}
 
-func (x *VideoData) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[0]
+func (x *GetUploadSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,51 +1625,129 @@ func (x *VideoData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VideoData.ProtoReflect.Descriptor instead.
-func (*VideoData) Descriptor() ([]byte, []int) {
-	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use GetUploadSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetUploadSessionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *VideoData) GetSampleRate() string {
+func (x *GetUploadSessionRequest) GetSessionId() string {
 	if x != nil {
-		return x.SampleRate
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *VideoData) GetData() []byte {
+// A resumable upload. Sessions expire, and their data is discarded, when they don't
+// receive any chunk for a while.
+type UploadSession struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId       string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	CommittedOffset uint64                 `protobuf:"varint,2,opt,name=committed_offset,json=committedOffset,proto3" json:"committed_offset,omitempty"` // Bytes received so far, where the next chunk must start.
+	ExpectedSize    uint64                 `protobuf:"varint,3,opt,name=expected_size,json=expectedSize,proto3" json:"expected_size,omitempty"`
+	ExpiresAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Size beyond which the upload is discarded, zero when unlimited.
+	// Set from the upload limit of the client creating the session.
+	MaxSize uint64 `protobuf:"varint,5,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+}
+
+func (x *UploadSession) Reset() {
+	*x = UploadSession{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadSession) ProtoMessage() {}
+
+func (x *UploadSession) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadSession.ProtoReflect.Descriptor instead.
+func (*UploadSession) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UploadSession) GetSessionId() string {
 	if x != nil {
-		return x.Data
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *UploadSession) GetCommittedOffset() uint64 {
+	if x != nil {
+		return x.CommittedOffset
+	}
+	return 0
+}
+
+func (x *UploadSession) GetExpectedSize() uint64 {
+	if x != nil {
+		return x.ExpectedSize
+	}
+	return 0
+}
+
+func (x *UploadSession) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
 	}
 	return nil
 }
 
-// Message to represent chunks of audio data being sent back to the client.
-type AudioData struct {
+func (x *UploadSession) GetMaxSize() uint64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+// A chunk of video data sent to an upload session.
+type UploadChunk struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Offset    uint64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // Position of the chunk in the video.
+	Data      []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
 }
 
-func (x *AudioData) Reset() {
-	*x = AudioData{}
+func (x *UploadChunk) Reset() {
+	*x = UploadChunk{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[1]
+		mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AudioData) String() string {
+func (x *UploadChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AudioData) ProtoMessage() {}
+func (*UploadChunk) ProtoMessage() {}
 
-func (x *AudioData) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[1]
+func (x *UploadChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -112,12 +1758,26 @@ func (x *AudioData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AudioData.ProtoReflect.Descriptor instead.
-func (*AudioData) Descriptor() ([]byte, []int) {
-	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use UploadChunk.ProtoReflect.Descriptor instead.
+func (*UploadChunk) Descriptor() ([]byte, []int) {
+	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *AudioData) GetData() []byte {
+func (x *UploadChunk) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *UploadChunk) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *UploadChunk) GetData() []byte {
 	if x != nil {
 		return x.Data
 	}
@@ -130,17 +1790,287 @@ var file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDesc = []byte{
 	0x0a, 0x34, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x75, 0x64, 0x69,
 	0x6f, 0x73, 0x74, 0x72, 0x69, 0x70, 0x70, 0x65, 0x72, 0x73, 0x76, 0x63, 0x2f, 0x76, 0x31, 0x2f,
 	0x61, 0x75, 0x64, 0x69, 0x6f, 0x73, 0x74, 0x72, 0x69, 0x70, 0x70, 0x65, 0x72, 0x73, 0x76, 0x63,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x40, 0x0a, 0x09, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x44,
-	0x61, 0x74, 0x61, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x72, 0x61,
-	0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
-	0x52, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x1f, 0x0a, 0x09, 0x41, 0x75, 0x64, 0x69,
-	0x6f, 0x44, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x32, 0x3b, 0x0a, 0x0d, 0x41, 0x75, 0x64,
-	0x69, 0x6f, 0x53, 0x74, 0x72, 0x69, 0x70, 0x70, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x0c, 0x45, 0x78,
-	0x74, 0x72, 0x61, 0x63, 0x74, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x12, 0x0a, 0x2e, 0x56, 0x69, 0x64,
-	0x65, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x0a, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x44, 0x61,
-	0x74, 0x61, 0x28, 0x01, 0x30, 0x01, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe4, 0x05, 0x0a, 0x0e, 0x45, 0x78, 0x74, 0x72,
+	0x61, 0x63, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x24, 0x0a, 0x06, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x41, 0x75, 0x64,
+	0x69, 0x6f, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x12, 0x24, 0x0a, 0x0e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f,
+	0x68, 0x7a, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x52, 0x61, 0x74, 0x65, 0x48, 0x7a, 0x12, 0x2a, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x73, 0x12, 0x4c, 0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x45, 0x78,
+	0x74, 0x72, 0x61, 0x63, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x12, 0x27, 0x0a, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x68, 0x61,
+	0x32, 0x35, 0x36, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x65, 0x78, 0x70, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x53, 0x68, 0x61, 0x32, 0x35, 0x36, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x78, 0x70,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0c, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x3c,
+	0x0a, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x37, 0x0a, 0x08,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x08, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x36, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x23, 0x0a,
+	0x0c, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x29, 0x0a, 0x0f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x6c, 0x61, 0x6e,
+	0x67, 0x75, 0x61, 0x67, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0e, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x4c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a,
+	0x0b, 0x61, 0x6c, 0x6c, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x08, 0x48, 0x01, 0x52, 0x0a, 0x61, 0x6c, 0x6c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73,
+	0x12, 0x2a, 0x0a, 0x11, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x75, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x09, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x1a, 0x41, 0x0a, 0x13, 0x43, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x05, 0x0a,
+	0x03, 0x65, 0x6e, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x22, 0x82,
+	0x02, 0x0a, 0x09, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2b, 0x0a, 0x07,
+	0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x45, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00,
+	0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x23, 0x0a, 0x0b, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x02, 0x18, 0x01, 0x52, 0x0a, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x52, 0x61, 0x74, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x46, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x2e,
+	0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0e, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74,
+	0x42, 0x02, 0x18, 0x01, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x28,
+	0x0a, 0x0e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x68, 0x7a,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x02, 0x18, 0x01, 0x52, 0x0c, 0x73, 0x61, 0x6d, 0x70,
+	0x6c, 0x65, 0x52, 0x61, 0x74, 0x65, 0x48, 0x7a, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x22, 0x9f, 0x01, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x1c, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x06, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x12, 0x25,
+	0x0a, 0x0e, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x62, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x70, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x22, 0xd1, 0x01, 0x0a, 0x07, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x12, 0x35, 0x0a, 0x08, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65,
+	0x5f, 0x68, 0x7a, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x73, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x52, 0x61, 0x74, 0x65, 0x48, 0x7a, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x22, 0x5c, 0x0a, 0x05, 0x54, 0x72, 0x61,
+	0x63, 0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x22, 0x9b, 0x01, 0x0a, 0x09, 0x41, 0x75, 0x64, 0x69,
+	0x6f, 0x44, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x27, 0x0a, 0x08, 0x70,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e,
+	0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x24, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x48,
+	0x00, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1e, 0x0a, 0x05, 0x74, 0x72,
+	0x61, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x54, 0x72, 0x61, 0x63,
+	0x6b, 0x48, 0x00, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0xb7, 0x01, 0x0a, 0x0a, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1f, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63,
+	0x6f, 0x64, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x6f, 0x64, 0x65,
+	0x63, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65,
+	0x5f, 0x68, 0x7a, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x73, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x52, 0x61, 0x74, 0x65, 0x48, 0x7a, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x22,
+	0xa5, 0x01, 0x0a, 0x09, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1f, 0x0a,
+	0x0b, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x35,
+	0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x69, 0x74, 0x5f, 0x72, 0x61, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x62, 0x69, 0x74, 0x52, 0x61, 0x74, 0x65,
+	0x12, 0x25, 0x0a, 0x07, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0b, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73, 0x22, 0xb7, 0x03, 0x0a, 0x03, 0x4a, 0x6f, 0x62, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x1f, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x09,
+	0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x25, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x08, 0x70,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x41, 0x0a,
+	0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x4a, 0x6f, 0x62, 0x2e, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x46, 0x0a, 0x11, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0f, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x1a, 0x41,
+	0x0a, 0x13, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x26, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x22, 0x29, 0x0a, 0x10, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a,
+	0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a,
+	0x6f, 0x62, 0x49, 0x64, 0x22, 0x32, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f, 0x62, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x09, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x2c, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74,
+	0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x04,
+	0x6a, 0x6f, 0x62, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x04, 0x2e, 0x4a, 0x6f, 0x62,
+	0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x22, 0x74, 0x0a, 0x15, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f,
+	0x61, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
+	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x22, 0x41, 0x0a, 0x1a,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0c, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x22,
+	0x38, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0xd4, 0x01, 0x0a, 0x0d, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x4f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x65, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65,
+	0x22, 0x58, 0x0a, 0x0b, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x2a, 0x9b, 0x01, 0x0a, 0x0b, 0x41,
+	0x75, 0x64, 0x69, 0x6f, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x1c, 0x0a, 0x18, 0x41, 0x55,
+	0x44, 0x49, 0x4f, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x55, 0x44, 0x49,
+	0x4f, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x57, 0x41, 0x56, 0x10, 0x01, 0x12, 0x15,
+	0x0a, 0x11, 0x41, 0x55, 0x44, 0x49, 0x4f, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x46,
+	0x4c, 0x41, 0x43, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x55, 0x44, 0x49, 0x4f, 0x5f, 0x46,
+	0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x4d, 0x50, 0x33, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x41,
+	0x55, 0x44, 0x49, 0x4f, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x4f, 0x50, 0x55, 0x53,
+	0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x55, 0x44, 0x49, 0x4f, 0x5f, 0x46, 0x4f, 0x52, 0x4d,
+	0x41, 0x54, 0x5f, 0x41, 0x41, 0x43, 0x10, 0x05, 0x2a, 0x9f, 0x01, 0x0a, 0x0d, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x1e, 0x0a, 0x1a, 0x43, 0x48,
+	0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4c, 0x41, 0x59, 0x4f, 0x55, 0x54, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x43, 0x48,
+	0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4c, 0x41, 0x59, 0x4f, 0x55, 0x54, 0x5f, 0x4d, 0x4f, 0x4e,
+	0x4f, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4c,
+	0x41, 0x59, 0x4f, 0x55, 0x54, 0x5f, 0x53, 0x54, 0x45, 0x52, 0x45, 0x4f, 0x10, 0x02, 0x12, 0x1f,
+	0x0a, 0x1b, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4c, 0x41, 0x59, 0x4f, 0x55, 0x54,
+	0x5f, 0x53, 0x55, 0x52, 0x52, 0x4f, 0x55, 0x4e, 0x44, 0x5f, 0x35, 0x5f, 0x31, 0x10, 0x03, 0x12,
+	0x19, 0x0a, 0x15, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4c, 0x41, 0x59, 0x4f, 0x55,
+	0x54, 0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x10, 0x04, 0x2a, 0x5c, 0x0a, 0x05, 0x53, 0x74,
+	0x61, 0x67, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x54,
+	0x41, 0x47, 0x45, 0x5f, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12,
+	0x14, 0x0a, 0x10, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x45, 0x58, 0x54, 0x52, 0x41, 0x43, 0x54,
+	0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x53,
+	0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x2a, 0xa3, 0x01, 0x0a, 0x0a, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x54, 0x52, 0x45, 0x41,
+	0x4d, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x41, 0x55, 0x44, 0x49, 0x4f, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x53,
+	0x54, 0x52, 0x45, 0x41, 0x4d, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x56, 0x49, 0x44, 0x45, 0x4f,
+	0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x53, 0x55, 0x42, 0x54, 0x49, 0x54, 0x4c, 0x45, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10,
+	0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x44, 0x41, 0x54, 0x41,
+	0x10, 0x04, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x41, 0x54, 0x54, 0x41, 0x43, 0x48, 0x4d, 0x45, 0x4e, 0x54, 0x10, 0x05, 0x2a, 0x99,
+	0x01, 0x0a, 0x08, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x15, 0x4a,
+	0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x45, 0x5f, 0x51, 0x55, 0x45, 0x55, 0x45, 0x44, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11,
+	0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e,
+	0x47, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45,
+	0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10,
+	0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44,
+	0x10, 0x04, 0x12, 0x16, 0x0a, 0x12, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f,
+	0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x45, 0x44, 0x10, 0x05, 0x32, 0x89, 0x04, 0x0a, 0x0d, 0x41,
+	0x75, 0x64, 0x69, 0x6f, 0x53, 0x74, 0x72, 0x69, 0x70, 0x70, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x0c,
+	0x45, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x12, 0x0a, 0x2e, 0x56,
+	0x69, 0x64, 0x65, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x0a, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f,
+	0x44, 0x61, 0x74, 0x61, 0x28, 0x01, 0x30, 0x01, 0x12, 0x26, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x12, 0x0a, 0x2e, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x44, 0x61,
+	0x74, 0x61, 0x1a, 0x0a, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x28, 0x01,
+	0x12, 0x1f, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4a, 0x6f, 0x62, 0x12, 0x0a, 0x2e,
+	0x56, 0x69, 0x64, 0x65, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x04, 0x2e, 0x4a, 0x6f, 0x62, 0x28,
+	0x01, 0x12, 0x1e, 0x0a, 0x06, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x12, 0x0e, 0x2e, 0x47, 0x65,
+	0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x04, 0x2e, 0x4a, 0x6f,
+	0x62, 0x12, 0x22, 0x0a, 0x08, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x12, 0x0e, 0x2e,
+	0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x04, 0x2e,
+	0x4a, 0x6f, 0x62, 0x30, 0x01, 0x12, 0x24, 0x0a, 0x09, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4a,
+	0x6f, 0x62, 0x12, 0x11, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4a, 0x6f, 0x62, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x04, 0x2e, 0x4a, 0x6f, 0x62, 0x12, 0x2f, 0x0a, 0x08, 0x4c,
+	0x69, 0x73, 0x74, 0x4a, 0x6f, 0x62, 0x73, 0x12, 0x10, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f,
+	0x62, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x0e,
+	0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16,
+	0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0a, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x44, 0x61,
+	0x74, 0x61, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3c, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x2e, 0x47,
+	0x65, 0x74, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x0c, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x12, 0x0c, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x0e, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x28, 0x01, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
 	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6c, 0x65, 0x73, 0x72, 0x2f, 0x61, 0x75, 0x64, 0x69, 0x6f,
 	0x73, 0x74, 0x72, 0x69, 0x70, 0x70, 0x65, 0x72, 0x73, 0x76, 0x63, 0x2f, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x2e, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
@@ -158,19 +2088,92 @@ func file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescGZIP() []b
 	return file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDescData
 }
 
-var file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_goTypes = []interface{}{
-	(*VideoData)(nil), // 0: VideoData
-	(*AudioData)(nil), // 1: AudioData
+	(AudioFormat)(0),                   // 0: AudioFormat
+	(ChannelLayout)(0),                 // 1: ChannelLayout
+	(Stage)(0),                         // 2: Stage
+	(StreamType)(0),                    // 3: StreamType
+	(JobState)(0),                      // 4: JobState
+	(*ExtractOptions)(nil),             // 5: ExtractOptions
+	(*VideoData)(nil),                  // 6: VideoData
+	(*Progress)(nil),                   // 7: Progress
+	(*Summary)(nil),                    // 8: Summary
+	(*Track)(nil),                      // 9: Track
+	(*AudioData)(nil),                  // 10: AudioData
+	(*StreamInfo)(nil),                 // 11: StreamInfo
+	(*MediaInfo)(nil),                  // 12: MediaInfo
+	(*Job)(nil),                        // 13: Job
+	(*GetJobRequest)(nil),              // 14: GetJobRequest
+	(*CancelJobRequest)(nil),           // 15: CancelJobRequest
+	(*ListJobsRequest)(nil),            // 16: ListJobsRequest
+	(*ListJobsResponse)(nil),           // 17: ListJobsResponse
+	(*DownloadResultRequest)(nil),      // 18: DownloadResultRequest
+	(*CreateUploadSessionRequest)(nil), // 19: CreateUploadSessionRequest
+	(*GetUploadSessionRequest)(nil),    // 20: GetUploadSessionRequest
+	(*UploadSession)(nil),              // 21: UploadSession
+	(*UploadChunk)(nil),                // 22: UploadChunk
+	nil,                                // 23: ExtractOptions.ClientMetadataEntry
+	nil,                                // 24: Job.ClientMetadataEntry
+	(*durationpb.Duration)(nil),        // 25: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),      // 26: google.protobuf.Timestamp
 }
 var file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_depIdxs = []int32{
-	0, // 0: AudioStripper.ExtractAudio:input_type -> VideoData
-	1, // 1: AudioStripper.ExtractAudio:output_type -> AudioData
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: ExtractOptions.format:type_name -> AudioFormat
+	1,  // 1: ExtractOptions.channels:type_name -> ChannelLayout
+	23, // 2: ExtractOptions.client_metadata:type_name -> ExtractOptions.ClientMetadataEntry
+	25, // 3: ExtractOptions.start_offset:type_name -> google.protobuf.Duration
+	25, // 4: ExtractOptions.duration:type_name -> google.protobuf.Duration
+	25, // 5: ExtractOptions.end_time:type_name -> google.protobuf.Duration
+	5,  // 6: VideoData.options:type_name -> ExtractOptions
+	0,  // 7: VideoData.format:type_name -> AudioFormat
+	1,  // 8: VideoData.channels:type_name -> ChannelLayout
+	2,  // 9: Progress.stage:type_name -> Stage
+	25, // 10: Progress.out_time:type_name -> google.protobuf.Duration
+	25, // 11: Summary.duration:type_name -> google.protobuf.Duration
+	7,  // 12: AudioData.progress:type_name -> Progress
+	8,  // 13: AudioData.summary:type_name -> Summary
+	9,  // 14: AudioData.track:type_name -> Track
+	3,  // 15: StreamInfo.type:type_name -> StreamType
+	25, // 16: MediaInfo.duration:type_name -> google.protobuf.Duration
+	11, // 17: MediaInfo.streams:type_name -> StreamInfo
+	4,  // 18: Job.state:type_name -> JobState
+	7,  // 19: Job.progress:type_name -> Progress
+	24, // 20: Job.client_metadata:type_name -> Job.ClientMetadataEntry
+	26, // 21: Job.created_at:type_name -> google.protobuf.Timestamp
+	26, // 22: Job.updated_at:type_name -> google.protobuf.Timestamp
+	26, // 23: Job.result_expires_at:type_name -> google.protobuf.Timestamp
+	4,  // 24: ListJobsRequest.state:type_name -> JobState
+	13, // 25: ListJobsResponse.jobs:type_name -> Job
+	26, // 26: UploadSession.expires_at:type_name -> google.protobuf.Timestamp
+	6,  // 27: AudioStripper.ExtractAudio:input_type -> VideoData
+	6,  // 28: AudioStripper.ProbeMedia:input_type -> VideoData
+	6,  // 29: AudioStripper.SubmitJob:input_type -> VideoData
+	14, // 30: AudioStripper.GetJob:input_type -> GetJobRequest
+	14, // 31: AudioStripper.WatchJob:input_type -> GetJobRequest
+	15, // 32: AudioStripper.CancelJob:input_type -> CancelJobRequest
+	16, // 33: AudioStripper.ListJobs:input_type -> ListJobsRequest
+	18, // 34: AudioStripper.DownloadResult:input_type -> DownloadResultRequest
+	19, // 35: AudioStripper.CreateUploadSession:input_type -> CreateUploadSessionRequest
+	20, // 36: AudioStripper.GetUploadSession:input_type -> GetUploadSessionRequest
+	22, // 37: AudioStripper.UploadChunks:input_type -> UploadChunk
+	10, // 38: AudioStripper.ExtractAudio:output_type -> AudioData
+	12, // 39: AudioStripper.ProbeMedia:output_type -> MediaInfo
+	13, // 40: AudioStripper.SubmitJob:output_type -> Job
+	13, // 41: AudioStripper.GetJob:output_type -> Job
+	13, // 42: AudioStripper.WatchJob:output_type -> Job
+	13, // 43: AudioStripper.CancelJob:output_type -> Job
+	17, // 44: AudioStripper.ListJobs:output_type -> ListJobsResponse
+	10, // 45: AudioStripper.DownloadResult:output_type -> AudioData
+	21, // 46: AudioStripper.CreateUploadSession:output_type -> UploadSession
+	21, // 47: AudioStripper.GetUploadSession:output_type -> UploadSession
+	21, // 48: AudioStripper.UploadChunks:output_type -> UploadSession
+	38, // [38:49] is the sub-list for method output_type
+	27, // [27:38] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_init() }
@@ -180,7 +2183,7 @@ func file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VideoData); i {
+			switch v := v.(*ExtractOptions); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -192,6 +2195,54 @@ func file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_init() {
 			}
 		}
 		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VideoData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Progress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Summary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Track); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AudioData); i {
 			case 0:
 				return &v.state
@@ -203,19 +2254,181 @@ func file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_init() {
 				return nil
 			}
 		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Job); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJobsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJobsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadResultRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateUploadSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetUploadSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadSession); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*ExtractOptions_Duration)(nil),
+		(*ExtractOptions_EndTime)(nil),
+		(*ExtractOptions_StreamIndex)(nil),
+		(*ExtractOptions_StreamLanguage)(nil),
+		(*ExtractOptions_AllStreams)(nil),
+	}
+	file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*VideoData_Options)(nil),
+		(*VideoData_Data)(nil),
+	}
+	file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes[5].OneofWrappers = []interface{}{
+		(*AudioData_Data)(nil),
+		(*AudioData_Progress)(nil),
+		(*AudioData_Summary)(nil),
+		(*AudioData_Track)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      5,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_goTypes,
 		DependencyIndexes: file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_depIdxs,
+		EnumInfos:         file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_enumTypes,
 		MessageInfos:      file_api_proto_audiostrippersvc_v1_audiostrippersvc_proto_msgTypes,
 	}.Build()
 	File_api_proto_audiostrippersvc_v1_audiostrippersvc_proto = out.File
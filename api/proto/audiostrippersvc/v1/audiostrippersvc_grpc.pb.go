@@ -22,7 +22,43 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AudioStripperClient interface {
+	// Extracts the audio of the video streamed after the options header.
+	// Uploads are limited in size, per API key when the client sends one in the x-api-key
+	// metadata. RPCs receiving a video fail with RESOURCE_EXHAUSTED as soon as it exceeds
+	// the limit, and the partial upload is discarded. Videos whose container format, told by
+	// their first bytes, is not allowed are rejected with INVALID_ARGUMENT before being stored.
+	// Extractions failing on the video itself are detailed with a google.rpc.ErrorInfo in the
+	// audiostrippersvc domain. Its reason is INVALID_DATA (INVALID_ARGUMENT), NO_AUDIO_STREAM or
+	// UNSUPPORTED_CODEC (FAILED_PRECONDITION), DISK_FULL or RESOURCE_LIMIT (RESOURCE_EXHAUSTED),
+	// or COMMAND_FAILED (INTERNAL) when the cause is unknown. Its stderr metadata holds the last lines logged by ffmpeg.
 	ExtractAudio(ctx context.Context, opts ...grpc.CallOption) (AudioStripper_ExtractAudioClient, error)
+	// Describes the container and streams of a video without extracting its audio.
+	// The options header is optional, only its expected_sha256 and expected_size are used.
+	// Failures are detailed with the same google.rpc.ErrorInfo as those of ExtractAudio.
+	ProbeMedia(ctx context.Context, opts ...grpc.CallOption) (AudioStripper_ProbeMediaClient, error)
+	// Uploads a video, with the same messages as ExtractAudio, and queues the extraction
+	// of its audio. Returns the queued job as soon as the upload completes.
+	SubmitJob(ctx context.Context, opts ...grpc.CallOption) (AudioStripper_SubmitJobClient, error)
+	GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*Job, error)
+	// Streams the job every time it changes, until it finishes.
+	WatchJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (AudioStripper_WatchJobClient, error)
+	// Cancels a queued or running job. Fails with FAILED_PRECONDITION if the job already finished.
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*Job, error)
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	// Streams the audio extracted by a succeeded job, with the same messages as ExtractAudio
+	// minus the progress updates, or a range of it to resume an interrupted download.
+	// Fails with FAILED_PRECONDITION if the job didn't succeed, and NOT_FOUND once its
+	// result expired.
+	DownloadResult(ctx context.Context, in *DownloadResultRequest, opts ...grpc.CallOption) (AudioStripper_DownloadResultClient, error)
+	// Starts a resumable upload. The video is sent with UploadChunks, over as many calls
+	// as needed, and used by naming the session in the upload_session_id option.
+	CreateUploadSession(ctx context.Context, in *CreateUploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	// Returns the session, including its committed offset to resume an upload from.
+	GetUploadSession(ctx context.Context, in *GetUploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	// Appends chunks to an upload session. Every chunk must start at the committed offset
+	// of the session, or the call fails with FAILED_PRECONDITION. Returns the session once
+	// the client closes the stream.
+	UploadChunks(ctx context.Context, opts ...grpc.CallOption) (AudioStripper_UploadChunksClient, error)
 }
 
 type audioStripperClient struct {
@@ -64,11 +100,258 @@ func (x *audioStripperExtractAudioClient) Recv() (*AudioData, error) {
 	return m, nil
 }
 
+func (c *audioStripperClient) ProbeMedia(ctx context.Context, opts ...grpc.CallOption) (AudioStripper_ProbeMediaClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioStripper_ServiceDesc.Streams[1], "/AudioStripper/ProbeMedia", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioStripperProbeMediaClient{stream}
+	return x, nil
+}
+
+type AudioStripper_ProbeMediaClient interface {
+	Send(*VideoData) error
+	CloseAndRecv() (*MediaInfo, error)
+	grpc.ClientStream
+}
+
+type audioStripperProbeMediaClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioStripperProbeMediaClient) Send(m *VideoData) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *audioStripperProbeMediaClient) CloseAndRecv() (*MediaInfo, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(MediaInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audioStripperClient) SubmitJob(ctx context.Context, opts ...grpc.CallOption) (AudioStripper_SubmitJobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioStripper_ServiceDesc.Streams[2], "/AudioStripper/SubmitJob", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioStripperSubmitJobClient{stream}
+	return x, nil
+}
+
+type AudioStripper_SubmitJobClient interface {
+	Send(*VideoData) error
+	CloseAndRecv() (*Job, error)
+	grpc.ClientStream
+}
+
+type audioStripperSubmitJobClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioStripperSubmitJobClient) Send(m *VideoData) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *audioStripperSubmitJobClient) CloseAndRecv() (*Job, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Job)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audioStripperClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*Job, error) {
+	out := new(Job)
+	err := c.cc.Invoke(ctx, "/AudioStripper/GetJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioStripperClient) WatchJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (AudioStripper_WatchJobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioStripper_ServiceDesc.Streams[3], "/AudioStripper/WatchJob", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioStripperWatchJobClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AudioStripper_WatchJobClient interface {
+	Recv() (*Job, error)
+	grpc.ClientStream
+}
+
+type audioStripperWatchJobClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioStripperWatchJobClient) Recv() (*Job, error) {
+	m := new(Job)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audioStripperClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*Job, error) {
+	out := new(Job)
+	err := c.cc.Invoke(ctx, "/AudioStripper/CancelJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioStripperClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, "/AudioStripper/ListJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioStripperClient) DownloadResult(ctx context.Context, in *DownloadResultRequest, opts ...grpc.CallOption) (AudioStripper_DownloadResultClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioStripper_ServiceDesc.Streams[4], "/AudioStripper/DownloadResult", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioStripperDownloadResultClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AudioStripper_DownloadResultClient interface {
+	Recv() (*AudioData, error)
+	grpc.ClientStream
+}
+
+type audioStripperDownloadResultClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioStripperDownloadResultClient) Recv() (*AudioData, error) {
+	m := new(AudioData)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audioStripperClient) CreateUploadSession(ctx context.Context, in *CreateUploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, "/AudioStripper/CreateUploadSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioStripperClient) GetUploadSession(ctx context.Context, in *GetUploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, "/AudioStripper/GetUploadSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioStripperClient) UploadChunks(ctx context.Context, opts ...grpc.CallOption) (AudioStripper_UploadChunksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioStripper_ServiceDesc.Streams[5], "/AudioStripper/UploadChunks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioStripperUploadChunksClient{stream}
+	return x, nil
+}
+
+type AudioStripper_UploadChunksClient interface {
+	Send(*UploadChunk) error
+	CloseAndRecv() (*UploadSession, error)
+	grpc.ClientStream
+}
+
+type audioStripperUploadChunksClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioStripperUploadChunksClient) Send(m *UploadChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *audioStripperUploadChunksClient) CloseAndRecv() (*UploadSession, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadSession)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AudioStripperServer is the server API for AudioStripper service.
 // All implementations must embed UnimplementedAudioStripperServer
 // for forward compatibility
 type AudioStripperServer interface {
+	// Extracts the audio of the video streamed after the options header.
+	// Uploads are limited in size, per API key when the client sends one in the x-api-key
+	// metadata. RPCs receiving a video fail with RESOURCE_EXHAUSTED as soon as it exceeds
+	// the limit, and the partial upload is discarded. Videos whose container format, told by
+	// their first bytes, is not allowed are rejected with INVALID_ARGUMENT before being stored.
+	// Extractions failing on the video itself are detailed with a google.rpc.ErrorInfo in the
+	// audiostrippersvc domain. Its reason is INVALID_DATA (INVALID_ARGUMENT), NO_AUDIO_STREAM or
+	// UNSUPPORTED_CODEC (FAILED_PRECONDITION), DISK_FULL or RESOURCE_LIMIT (RESOURCE_EXHAUSTED),
+	// or COMMAND_FAILED (INTERNAL) when the cause is unknown. Its stderr metadata holds the last lines logged by ffmpeg.
 	ExtractAudio(AudioStripper_ExtractAudioServer) error
+	// Describes the container and streams of a video without extracting its audio.
+	// The options header is optional, only its expected_sha256 and expected_size are used.
+	// Failures are detailed with the same google.rpc.ErrorInfo as those of ExtractAudio.
+	ProbeMedia(AudioStripper_ProbeMediaServer) error
+	// Uploads a video, with the same messages as ExtractAudio, and queues the extraction
+	// of its audio. Returns the queued job as soon as the upload completes.
+	SubmitJob(AudioStripper_SubmitJobServer) error
+	GetJob(context.Context, *GetJobRequest) (*Job, error)
+	// Streams the job every time it changes, until it finishes.
+	WatchJob(*GetJobRequest, AudioStripper_WatchJobServer) error
+	// Cancels a queued or running job. Fails with FAILED_PRECONDITION if the job already finished.
+	CancelJob(context.Context, *CancelJobRequest) (*Job, error)
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	// Streams the audio extracted by a succeeded job, with the same messages as ExtractAudio
+	// minus the progress updates, or a range of it to resume an interrupted download.
+	// Fails with FAILED_PRECONDITION if the job didn't succeed, and NOT_FOUND once its
+	// result expired.
+	DownloadResult(*DownloadResultRequest, AudioStripper_DownloadResultServer) error
+	// Starts a resumable upload. The video is sent with UploadChunks, over as many calls
+	// as needed, and used by naming the session in the upload_session_id option.
+	CreateUploadSession(context.Context, *CreateUploadSessionRequest) (*UploadSession, error)
+	// Returns the session, including its committed offset to resume an upload from.
+	GetUploadSession(context.Context, *GetUploadSessionRequest) (*UploadSession, error)
+	// Appends chunks to an upload session. Every chunk must start at the committed offset
+	// of the session, or the call fails with FAILED_PRECONDITION. Returns the session once
+	// the client closes the stream.
+	UploadChunks(AudioStripper_UploadChunksServer) error
 	mustEmbedUnimplementedAudioStripperServer()
 }
 
@@ -79,6 +362,36 @@ type UnimplementedAudioStripperServer struct {
 func (UnimplementedAudioStripperServer) ExtractAudio(AudioStripper_ExtractAudioServer) error {
 	return status.Errorf(codes.Unimplemented, "method ExtractAudio not implemented")
 }
+func (UnimplementedAudioStripperServer) ProbeMedia(AudioStripper_ProbeMediaServer) error {
+	return status.Errorf(codes.Unimplemented, "method ProbeMedia not implemented")
+}
+func (UnimplementedAudioStripperServer) SubmitJob(AudioStripper_SubmitJobServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubmitJob not implemented")
+}
+func (UnimplementedAudioStripperServer) GetJob(context.Context, *GetJobRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedAudioStripperServer) WatchJob(*GetJobRequest, AudioStripper_WatchJobServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchJob not implemented")
+}
+func (UnimplementedAudioStripperServer) CancelJob(context.Context, *CancelJobRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedAudioStripperServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedAudioStripperServer) DownloadResult(*DownloadResultRequest, AudioStripper_DownloadResultServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadResult not implemented")
+}
+func (UnimplementedAudioStripperServer) CreateUploadSession(context.Context, *CreateUploadSessionRequest) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUploadSession not implemented")
+}
+func (UnimplementedAudioStripperServer) GetUploadSession(context.Context, *GetUploadSessionRequest) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUploadSession not implemented")
+}
+func (UnimplementedAudioStripperServer) UploadChunks(AudioStripper_UploadChunksServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadChunks not implemented")
+}
 func (UnimplementedAudioStripperServer) mustEmbedUnimplementedAudioStripperServer() {}
 
 // UnsafeAudioStripperServer may be embedded to opt out of forward compatibility for this service.
@@ -118,13 +431,244 @@ func (x *audioStripperExtractAudioServer) Recv() (*VideoData, error) {
 	return m, nil
 }
 
+func _AudioStripper_ProbeMedia_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AudioStripperServer).ProbeMedia(&audioStripperProbeMediaServer{stream})
+}
+
+type AudioStripper_ProbeMediaServer interface {
+	SendAndClose(*MediaInfo) error
+	Recv() (*VideoData, error)
+	grpc.ServerStream
+}
+
+type audioStripperProbeMediaServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioStripperProbeMediaServer) SendAndClose(m *MediaInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *audioStripperProbeMediaServer) Recv() (*VideoData, error) {
+	m := new(VideoData)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _AudioStripper_SubmitJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AudioStripperServer).SubmitJob(&audioStripperSubmitJobServer{stream})
+}
+
+type AudioStripper_SubmitJobServer interface {
+	SendAndClose(*Job) error
+	Recv() (*VideoData, error)
+	grpc.ServerStream
+}
+
+type audioStripperSubmitJobServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioStripperSubmitJobServer) SendAndClose(m *Job) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *audioStripperSubmitJobServer) Recv() (*VideoData, error) {
+	m := new(VideoData)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _AudioStripper_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioStripperServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioStripper/GetJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioStripperServer).GetJob(ctx, req.(*GetJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioStripper_WatchJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetJobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudioStripperServer).WatchJob(m, &audioStripperWatchJobServer{stream})
+}
+
+type AudioStripper_WatchJobServer interface {
+	Send(*Job) error
+	grpc.ServerStream
+}
+
+type audioStripperWatchJobServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioStripperWatchJobServer) Send(m *Job) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AudioStripper_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioStripperServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioStripper/CancelJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioStripperServer).CancelJob(ctx, req.(*CancelJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioStripper_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioStripperServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioStripper/ListJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioStripperServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioStripper_DownloadResult_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadResultRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudioStripperServer).DownloadResult(m, &audioStripperDownloadResultServer{stream})
+}
+
+type AudioStripper_DownloadResultServer interface {
+	Send(*AudioData) error
+	grpc.ServerStream
+}
+
+type audioStripperDownloadResultServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioStripperDownloadResultServer) Send(m *AudioData) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AudioStripper_CreateUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioStripperServer).CreateUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioStripper/CreateUploadSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioStripperServer).CreateUploadSession(ctx, req.(*CreateUploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioStripper_GetUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioStripperServer).GetUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioStripper/GetUploadSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioStripperServer).GetUploadSession(ctx, req.(*GetUploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioStripper_UploadChunks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AudioStripperServer).UploadChunks(&audioStripperUploadChunksServer{stream})
+}
+
+type AudioStripper_UploadChunksServer interface {
+	SendAndClose(*UploadSession) error
+	Recv() (*UploadChunk, error)
+	grpc.ServerStream
+}
+
+type audioStripperUploadChunksServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioStripperUploadChunksServer) SendAndClose(m *UploadSession) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *audioStripperUploadChunksServer) Recv() (*UploadChunk, error) {
+	m := new(UploadChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AudioStripper_ServiceDesc is the grpc.ServiceDesc for AudioStripper service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var AudioStripper_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "AudioStripper",
 	HandlerType: (*AudioStripperServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetJob",
+			Handler:    _AudioStripper_GetJob_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _AudioStripper_CancelJob_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _AudioStripper_ListJobs_Handler,
+		},
+		{
+			MethodName: "CreateUploadSession",
+			Handler:    _AudioStripper_CreateUploadSession_Handler,
+		},
+		{
+			MethodName: "GetUploadSession",
+			Handler:    _AudioStripper_GetUploadSession_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "ExtractAudio",
@@ -132,6 +676,31 @@ var AudioStripper_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ProbeMedia",
+			Handler:       _AudioStripper_ProbeMedia_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SubmitJob",
+			Handler:       _AudioStripper_SubmitJob_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchJob",
+			Handler:       _AudioStripper_WatchJob_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DownloadResult",
+			Handler:       _AudioStripper_DownloadResult_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadChunks",
+			Handler:       _AudioStripper_UploadChunks_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "api/proto/audiostrippersvc/v1/audiostrippersvc.proto",
 }
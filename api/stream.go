@@ -0,0 +1,380 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/sniff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// streamable reports whether the extraction can be streamed, that is without probing the video first.
+func streamable(input *audiostripper.ExtractAudioInput, opts *apiv1.ExtractOptions) bool {
+	return opts.UploadSessionId == "" &&
+		input.Start == 0 && input.End == 0 &&
+		input.Stream == nil && !input.AllStreams
+}
+
+// streamAudio pipes the video received on video into the extraction, starting with the data carried
+// by the first message, and sends the audio to the client as it is extracted.
+// The upload is only checked against the upload limit: uploads to be verified can't be streamed.
+func (s *GRPCServer) streamAudio(stream apiv1.AudioStripper_ExtractAudioServer, video videoStream, first *apiv1.VideoData, input *audiostripper.ExtractAudioInput) error {
+	r := videoReader{stream: video, maxSize: s.uploadLimit(stream.Context())}
+
+	if err := r.accept(first.GetData()); err != nil {
+		return err
+	}
+
+	release, err := s.acquireWorker(stream)
+	if err != nil {
+		return err
+	}
+
+	// The video is received ahead of the extraction, which reads it from the spool
+	spool := newVideoSpool(s.workDir, s.maxInMemorySize)
+	uploaded := make(chan error, 1)
+	go func() { uploaded <- spool.receive(&r) }()
+
+	summary, err := s.pipeAudio(stream, input, spool, r.received.Load)
+	release()
+	spool.Close()
+
+	// A failed upload explains the failure of the extraction fed with it
+	if err := spool.uploadErr(); err != nil {
+		return err
+	}
+	if err != nil {
+		return s.extractionError(stream.Context(), err)
+	}
+
+	// The extraction may not have read the video to the end, the client is done sending once it was
+	if err := <-uploaded; err != nil {
+		return err
+	}
+	return sendSummary(stream, summary)
+}
+
+// extractInMemory pipes a video kept in memory into the extraction and sends the audio to the client
+// as it is extracted, without touching the disk.
+func (s *GRPCServer) extractInMemory(stream apiv1.AudioStripper_ExtractAudioServer, up *upload, input *audiostripper.ExtractAudioInput) error {
+	release, err := s.acquireWorker(stream)
+	if err != nil {
+		return err
+	}
+
+	summary, err := s.pipeAudio(stream, input, bytes.NewReader(up.data), func() uint64 { return up.size })
+	release()
+
+	if err != nil {
+		return s.extractionError(stream.Context(), err)
+	}
+	return sendSummary(stream, summary)
+}
+
+// pipeAudio runs the extraction on the video read from r and sends the audio to the client as it is extracted.
+// received returns the number of video bytes received so far, for the progress updates.
+// It returns the summary of the audio sent, as described by the extraction, and the error of the extraction service as is.
+func (s *GRPCServer) pipeAudio(stream apiv1.AudioStripper_ExtractAudioServer, input *audiostripper.ExtractAudioInput, r io.Reader, received func() uint64) (*apiv1.Summary, error) {
+	// The audio, the progress and the upload are handled by the goroutines of the extraction command
+	sender := syncSender{stream: stream}
+	w := audioWriter{stream: &sender, hash: sha256.New()}
+
+	input.OnProgress = func(p audiostripper.Progress) {
+		progress := apiv1.Progress{
+			Stage:         apiv1.Stage_STAGE_EXTRACTING,
+			BytesReceived: received(),
+			OutTime:       durationpb.New(p.OutTime),
+			Percent:       float32(p.Percent()),
+		}
+
+		// A failed send means the client is gone, the audio will fail to send as well
+		if err := sender.Send(&apiv1.AudioData{Payload: &apiv1.AudioData_Progress{Progress: &progress}}); err != nil {
+			s.logger.Warn("Failed to send progress", slog.String("error", err.Error()))
+		}
+	}
+
+	media, err := s.service.StreamAudio(stream.Context(), input, r, &w)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := newSummary(media)
+	summary.TotalBytes = w.size
+	summary.Sha256 = hex.EncodeToString(w.hash.Sum(nil))
+	return summary, nil
+}
+
+// sendSummary sends the summary of the extracted audio to the client.
+func sendSummary(stream audioStream, summary *apiv1.Summary) error {
+	if err := stream.Send(&apiv1.AudioData{Payload: &apiv1.AudioData_Summary{Summary: summary}}); err != nil {
+		return status.Errorf(codes.Internal, "failed to send summary to client: %s", err)
+	}
+	return nil
+}
+
+// receiveHead receives the first messages of the video, until they tell its format and whether it needs seeking.
+// Videos that can't be told within the in-memory limit are assumed to need seeking, the head is received
+// up to sniff.HeadSize bytes whatever the limit to tell the format of the video.
+func (s *GRPCServer) receiveHead(stream videoStream, first *apiv1.VideoData) (msgs []*apiv1.VideoData, needsSeeking bool, err error) {
+	msgs = []*apiv1.VideoData{first}
+	head := append([]byte(nil), first.GetData()...)
+
+	for {
+		seek, err := sniff.NeedsSeeking(head)
+		told := !errors.Is(err, sniff.ErrShortHead)
+
+		switch {
+		case told && s.headComplete(head):
+			return msgs, seek, nil
+		case !told && len(head) >= max(s.maxInMemorySize, sniff.HeadSize):
+			return msgs, true, nil
+		}
+
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return msgs, !told || seek, nil
+		}
+		if err != nil {
+			return nil, false, status.Errorf(codes.Unknown, "failed to receive data: %v", err)
+		}
+
+		msgs = append(msgs, msg)
+		head = append(head, msg.GetData()...)
+	}
+}
+
+// replayStream is a videoStream returning the given messages before the ones of stream.
+type replayStream struct {
+	msgs   []*apiv1.VideoData
+	stream videoStream
+}
+
+func (r *replayStream) Context() context.Context {
+	return r.stream.Context()
+}
+
+func (r *replayStream) Recv() (*apiv1.VideoData, error) {
+	if len(r.msgs) == 0 {
+		return r.stream.Recv()
+	}
+
+	msg := r.msgs[0]
+	r.msgs = r.msgs[1:]
+	return msg, nil
+}
+
+// videoReader reads the video received on a stream, checking it against the upload limit.
+type videoReader struct {
+	stream   videoStream
+	maxSize  uint64 // Zero when unlimited
+	pending  []byte
+	received atomic.Uint64 // Read by the progress updates
+	err      error         // The error that ended the upload
+}
+
+func (r *videoReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	for len(r.pending) == 0 {
+		msg, err := r.stream.Recv()
+		if err == io.EOF {
+			return 0, io.EOF
+		}
+		if err != nil {
+			r.err = status.Errorf(codes.Unknown, "failed to receive data: %v", err)
+			return 0, r.err
+		}
+
+		if msg.GetOptions() != nil {
+			r.err = status.Error(codes.InvalidArgument, "options must only be sent in the first message")
+			return 0, r.err
+		}
+
+		if err := r.accept(msg.GetData()); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// accept queues a chunk of the video to be read.
+func (r *videoReader) accept(data []byte) error {
+	received := r.received.Add(uint64(len(data)))
+
+	if err := checkUploadLimit(received, r.maxSize); err != nil {
+		r.err = err
+		return r.err
+	}
+
+	r.pending = data
+	return nil
+}
+
+// videoSpool holds the video received from a client until the extraction reads it.
+// Sends block once the flow control window of the stream is full, so a client sending the whole video
+// before reading the audio would stall its own upload if it was paced by the extraction: the extraction
+// would wait for the client to read the audio, and the client for the extraction to read the video.
+// The video is therefore received on its own, the data not read yet kept in memory up to limit bytes
+// and spilled to a temp file in dir beyond.
+type videoSpool struct {
+	dir   string
+	limit int
+
+	mu     sync.Mutex
+	cond   sync.Cond
+	mem    []byte   // Data not read yet, until spilled
+	file   *os.File // Set once spilled, the data is then appended to it
+	read   int64    // Offset in the file of the data not read yet
+	size   int64    // Size of the file
+	err    error    // The error that ended the upload, io.EOF once complete
+	closed bool     // Set once the extraction is done, the data still received is discarded
+}
+
+func newVideoSpool(dir string, limit int) *videoSpool {
+	s := videoSpool{dir: dir, limit: limit}
+	s.cond.L = &s.mu
+	return &s
+}
+
+// receive receives the video read from r into the spool, until it is complete or fails.
+func (s *videoSpool) receive(r io.Reader) error {
+	_, err := io.Copy(s, r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+	if err == nil {
+		s.err = io.EOF
+	}
+	s.cond.Broadcast()
+	return err
+}
+
+func (s *videoSpool) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return len(p), nil
+	}
+
+	if s.file == nil && len(s.mem)+len(p) > s.limit {
+		f, err := os.CreateTemp(s.dir, "input-*")
+		if err != nil {
+			return 0, status.Errorf(codes.Internal, "failed to create temp file: %v", err)
+		}
+		s.file = f
+	}
+
+	if s.file == nil {
+		s.mem = append(s.mem, p...)
+	} else {
+		n, err := s.file.WriteAt(p, s.size)
+		s.size += int64(n)
+		if err != nil {
+			return n, status.Errorf(codes.Internal, "failed to write to temp file: %v", err)
+		}
+	}
+
+	s.cond.Broadcast()
+	return len(p), nil
+}
+
+// Read reads the video in the order it was received, the data held in memory coming before the spilled one.
+func (s *videoSpool) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.mem) == 0 && s.read == s.size && s.err == nil && !s.closed {
+		s.cond.Wait()
+	}
+
+	switch {
+	case s.closed:
+		return 0, io.ErrClosedPipe
+	case len(s.mem) > 0:
+		n := copy(p, s.mem)
+		s.mem = s.mem[n:]
+		return n, nil
+	case s.read < s.size:
+		n, err := s.file.ReadAt(p[:min(int64(len(p)), s.size-s.read)], s.read)
+		s.read += int64(n)
+		return n, err
+	}
+	return 0, s.err
+}
+
+// uploadErr returns the error that ended the upload, nil while it is in progress or once complete.
+func (s *videoSpool) uploadErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
+
+// Close discards the data not read yet and the data still to be received, and removes the temp file.
+func (s *videoSpool) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	s.mem = nil
+
+	if s.file != nil {
+		s.file.Close()
+		os.Remove(s.file.Name())
+		s.file = nil
+	}
+	s.cond.Broadcast()
+}
+
+// audioWriter sends the audio written to it to the client, hashing it for the summary.
+type audioWriter struct {
+	stream audioStream
+	hash   hash.Hash
+	size   uint64
+}
+
+func (w *audioWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&apiv1.AudioData{Payload: &apiv1.AudioData_Data{Data: p}}); err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to send chunk to client: %s", err)
+	}
+
+	w.hash.Write(p)
+	w.size += uint64(len(p))
+	return len(p), nil
+}
+
+// syncSender serializes the messages sent on stream from several goroutines.
+type syncSender struct {
+	mu     sync.Mutex
+	stream audioStream
+}
+
+func (s *syncSender) Send(msg *apiv1.AudioData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stream.Send(msg)
+}
@@ -0,0 +1,344 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestExtractAudioStreaming(t *testing.T) {
+	mockService := mockAudioStripperService{
+		StreamAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput, r io.Reader, w io.Writer) (*audiostripper.MediaInfo, error) {
+			assert.Equal(t, 44100, in.SampleRate)
+			assert.Empty(t, in.FilePath)
+
+			video, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, "videoDataChunk1videoDataChunk2", string(video))
+
+			in.OnProgress(audiostripper.Progress{})
+
+			for _, chunk := range []string{"audio", "Data"} {
+				if _, err := w.Write([]byte(chunk)); err != nil {
+					return nil, err
+				}
+			}
+			return &audiostripper.MediaInfo{}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	stream, err := client.ExtractAudio(context.TODO())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true})))
+	require.NoError(t, stream.Send(dataMsg("videoDataChunk1")))
+	require.NoError(t, stream.Send(dataMsg("videoDataChunk2")))
+	require.NoError(t, stream.CloseSend())
+
+	var (
+		audio []byte
+		last  *apiv1.AudioData
+	)
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		if progress := msg.GetProgress(); progress != nil {
+			assert.Equal(t, apiv1.Stage_STAGE_EXTRACTING, progress.Stage)
+		}
+
+		audio = append(audio, msg.GetData()...)
+		last = msg
+	}
+
+	assert.Equal(t, "audioData", string(audio))
+
+	checksum := sha256.Sum256([]byte("audioData"))
+
+	summary := last.GetSummary()
+	require.NotNil(t, summary)
+	assert.Equal(t, uint64(len("audioData")), summary.TotalBytes)
+	assert.Equal(t, hex.EncodeToString(checksum[:]), summary.Sha256)
+}
+
+func TestExtractAudioStreamingUploadBeforeReading(t *testing.T) {
+	mockService := mockAudioStripperService{
+		StreamAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput, r io.Reader, w io.Writer) (*audiostripper.MediaInfo, error) {
+			// Echo the video as the audio, extracted as the video is read
+			_, err := io.Copy(w, r)
+			return &audiostripper.MediaInfo{}, err
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.ExtractAudio(ctx)
+	require.NoError(t, err)
+
+	// Far beyond the flow control windows, the audio the client doesn't read would stall the extraction
+	const (
+		numChunks = 256
+		chunkSize = 32 << 10
+	)
+	chunk := strings.Repeat("v", chunkSize)
+
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true})))
+	for i := 0; i < numChunks; i++ {
+		require.NoError(t, stream.Send(dataMsg(chunk)))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	var size int
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		size += len(msg.GetData())
+	}
+	assert.Equal(t, numChunks*chunkSize, size)
+}
+
+func TestExtractAudioStreamingFallback(t *testing.T) {
+	video := mp4HeadHelper() + "videoData"
+
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			data, err := os.ReadFile(in.FilePath)
+			assert.NoError(t, err)
+			assert.Equal(t, video, string(data))
+
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	testCases := []struct {
+		name string
+		opts *apiv1.ExtractOptions
+		msgs []*apiv1.VideoData
+	}{
+		{
+			name: "video needing seeking",
+			opts: &apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true},
+			msgs: []*apiv1.VideoData{dataMsg(video[:6]), dataMsg(video[6:])},
+		},
+		{
+			name: "stream selection",
+			opts: &apiv1.ExtractOptions{
+				SampleRateHz: 44100,
+				Streaming:    true,
+				Stream:       &apiv1.ExtractOptions_StreamIndex{StreamIndex: 1},
+			},
+			msgs: []*apiv1.VideoData{dataMsg(video)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			audio, err := extractAudioHelper(t, client, append([]*apiv1.VideoData{optionsMsg(tc.opts)}, tc.msgs...)...)
+			require.NoError(t, err)
+			assert.Equal(t, video, string(audio))
+		})
+	}
+}
+
+func TestExtractAudioStreamingIntegrity(t *testing.T) {
+	// Streams with integrity options are verified before ffmpeg runs, as without streaming
+	mockService := mockAudioStripperService{
+		StreamAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput, r io.Reader, w io.Writer) (*audiostripper.MediaInfo, error) {
+			t.Error("the extraction must not run on an upload failing verification")
+			return &audiostripper.MediaInfo{}, nil
+		},
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			t.Error("the extraction must not run on an upload failing verification")
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	testCases := []struct {
+		name string
+		opts *apiv1.ExtractOptions
+	}{
+		{
+			name: "larger than expected",
+			opts: &apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true, ExpectedSize: 5},
+		},
+		{
+			name: "smaller than expected",
+			opts: &apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true, ExpectedSize: 20},
+		},
+		{
+			name: "checksum mismatch",
+			opts: &apiv1.ExtractOptions{SampleRateHz: 44100, Streaming: true, ExpectedSha256: hex.EncodeToString(make([]byte, sha256.Size))},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := extractAudioHelper(t, client, optionsMsg(tc.opts), dataMsg("videoData"))
+			require.Equal(t, codes.DataLoss, status.Code(err))
+		})
+	}
+}
+
+func TestExtractAudioInMemory(t *testing.T) {
+	mockService := mockAudioStripperService{
+		StreamAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput, r io.Reader, w io.Writer) (*audiostripper.MediaInfo, error) {
+			assert.Empty(t, in.FilePath)
+
+			video, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, "videoDataChunk1videoDataChunk2", string(video))
+
+			in.OnProgress(audiostripper.Progress{OutTime: 2 * time.Second})
+
+			if _, err = w.Write([]byte("audioData")); err != nil {
+				return nil, err
+			}
+
+			// The piped audio is described by the extraction
+			return &audiostripper.MediaInfo{
+				Duration: 3 * time.Second,
+				Streams: []audiostripper.StreamInfo{
+					{Type: audiostripper.StreamTypeAudio, Codec: "pcm_s16le", SampleRate: 16000, Channels: 1},
+				},
+			}, nil
+		},
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			// Uploads beyond the threshold and extractions probing the video go through the disk
+			data, err := os.ReadFile(in.FilePath)
+			assert.NoError(t, err)
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, os.WriteFile(in.FilePath, append([]byte("fromDisk:"), data...), 0o600)
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService, WithMaxInMemorySize(30))
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	checksum := sha256.Sum256([]byte("videoDataChunk1videoDataChunk2"))
+
+	stream, err := client.ExtractAudio(context.TODO())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{
+		SampleRateHz:   16000,
+		Channels:       apiv1.ChannelLayout_CHANNEL_LAYOUT_MONO,
+		ExpectedSha256: hex.EncodeToString(checksum[:]),
+	})))
+	require.NoError(t, stream.Send(dataMsg("videoDataChunk1")))
+	require.NoError(t, stream.Send(dataMsg("videoDataChunk2")))
+	require.NoError(t, stream.CloseSend())
+
+	var (
+		audio []byte
+		last  *apiv1.AudioData
+	)
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		audio = append(audio, msg.GetData()...)
+		last = msg
+	}
+
+	assert.Equal(t, "audioData", string(audio))
+
+	summary := last.GetSummary()
+	require.NotNil(t, summary)
+	assert.Equal(t, uint64(len("audioData")), summary.TotalBytes)
+	assert.Equal(t, 3*time.Second, summary.Duration.AsDuration())
+	assert.Equal(t, uint32(16000), summary.SampleRateHz)
+	assert.Equal(t, uint32(1), summary.Channels)
+	assert.Equal(t, "pcm_s16le", summary.Codec)
+
+	t.Run("beyond the threshold", func(t *testing.T) {
+		audio, err := extractAudioHelper(t, client,
+			optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 16000}),
+			dataMsg("videoDataChunk1videoDataChunk2"),
+			dataMsg("videoDataChunk3"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "fromDisk:videoDataChunk1videoDataChunk2videoDataChunk3", string(audio))
+	})
+
+	t.Run("video needing seeking", func(t *testing.T) {
+		audio, err := extractAudioHelper(t, client, optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 16000}), dataMsg(mp4HeadHelper()))
+		require.NoError(t, err)
+		assert.Equal(t, "fromDisk:"+mp4HeadHelper(), string(audio))
+	})
+
+	t.Run("trimmed", func(t *testing.T) {
+		audio, err := extractAudioHelper(t, client,
+			optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 16000, StartOffset: durationpb.New(time.Second)}),
+			dataMsg("videoData"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "fromDisk:videoData", string(audio))
+	})
+
+	t.Run("corrupted upload", func(t *testing.T) {
+		_, err := extractAudioHelper(t, client,
+			optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 16000, ExpectedSize: 20}),
+			dataMsg("videoData"),
+		)
+		require.Equal(t, codes.DataLoss, status.Code(err))
+	})
+}
+
+// mp4HeadHelper returns the start of an MP4 file with its media data before its index.
+func mp4HeadHelper() string {
+	head := make([]byte, 16)
+	binary.BigEndian.PutUint32(head, 8)
+	copy(head[4:], "ftyp")
+	binary.BigEndian.PutUint32(head[8:], 1024)
+	copy(head[12:], "mdat")
+	return string(head)
+}
@@ -0,0 +1,314 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// videoStream is the receiving side of the RPCs accepting a chunked video upload.
+type videoStream interface {
+	Recv() (*apiv1.VideoData, error)
+	Context() context.Context
+}
+
+// upload is a video received from a client.
+type upload struct {
+	path   string // Empty when the video is kept in memory
+	data   []byte // The video, when kept in memory
+	size   uint64
+	format string // Container format of the video, empty when not enforced
+}
+
+// receiveVideo receives the video of a stream, either from the upload session named in opts
+// or from the stream itself, starting with the data carried by the first message.
+// Videos received on the stream are kept in memory up to memLimit bytes.
+// Videos larger than the upload limit of the client are rejected with RESOURCE_EXHAUSTED.
+func (s *GRPCServer) receiveVideo(stream videoStream, first *apiv1.VideoData, opts *apiv1.ExtractOptions, memLimit int, onChunk func(received uint64) error) (*upload, error) {
+	maxSize := s.uploadLimit(stream.Context())
+
+	// Don't wait for the data to reject an upload announced too large
+	if err := checkUploadLimit(opts.ExpectedSize, maxSize); err != nil {
+		return nil, err
+	}
+
+	if opts.UploadSessionId == "" {
+		return s.receiveUpload(stream, first, opts, memLimit, maxSize, onChunk)
+	}
+	return s.sessionUpload(stream, first, opts)
+}
+
+// sessionUpload completes the upload session named in opts and verifies its video against
+// the size and checksum announced in opts. The stream must not carry any data itself.
+// Only the client that created the session can use it, the upload limit of the client bounding it already.
+// The video is removed if it fails verification or is not of an allowed format.
+func (s *GRPCServer) sessionUpload(stream videoStream, first *apiv1.VideoData, opts *apiv1.ExtractOptions) (*upload, error) {
+	if len(first.GetData()) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "video data can't be sent along an upload session")
+	}
+
+	msg, err := stream.Recv()
+	if err == nil {
+		if msg.GetOptions() != nil {
+			return nil, status.Error(codes.InvalidArgument, "options must only be sent in the first message")
+		}
+		return nil, status.Error(codes.InvalidArgument, "video data can't be sent along an upload session")
+	}
+	if err != io.EOF {
+		return nil, status.Errorf(codes.Unknown, "failed to receive data: %v", err)
+	}
+
+	if _, err := s.callerSession(stream.Context(), opts.UploadSessionId); err != nil {
+		return nil, err
+	}
+
+	path, size, err := s.uploads.Complete(opts.UploadSessionId)
+	if err != nil {
+		return nil, uploadSessionError(err)
+	}
+
+	// Its head was checked when uploaded unless sent over several streams, the format still needs to be told
+	format, err := s.detectFileFormat(path)
+
+	if err == nil {
+		var checksum []byte
+		if checksum, err = fileChecksum(path); err == nil {
+			err = verifyUpload(opts, size, checksum)
+		} else {
+			err = status.Errorf(codes.Internal, "failed to read upload: %v", err)
+		}
+	}
+
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return &upload{path: path, size: size, format: format}, nil
+}
+
+// fileChecksum returns the SHA-256 of the file at path.
+func fileChecksum(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// receiveUpload receives the video sent on stream, starting with the data carried by the first message,
+// and verifies it against the size and checksum announced in opts. The video is kept in memory up to
+// memLimit bytes and spilled to a temp file beyond. Uploads growing beyond maxSize bytes, unless zero,
+// are aborted with RESOURCE_EXHAUSTED.
+// The video is rejected unless of an allowed format, told by its first bytes before any of them is written.
+// onChunk, if set, is called with the number of bytes received so far after every chunk.
+// The temp file is removed if the upload fails.
+func (s *GRPCServer) receiveUpload(stream videoStream, first *apiv1.VideoData, opts *apiv1.ExtractOptions, memLimit int, maxSize uint64, onChunk func(received uint64) error) (_ *upload, err error) {
+	buffer := spillBuffer{dir: s.workDir, limit: memLimit}
+
+	// Without memory to spare the video goes straight to the disk
+	if memLimit == 0 {
+		if err := buffer.spill(); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create temp file: %v", err)
+		}
+	}
+
+	defer func() {
+		if err != nil {
+			buffer.Close()
+			buffer.Remove()
+		}
+	}()
+
+	var (
+		received uint64
+		format   string
+		head     []byte // The start of the video, held back until it tells its format
+		told     bool
+	)
+
+	// Hash the upload while writing it so it can be checked against the client checksum
+	uploadHash := sha256.New()
+	w := io.MultiWriter(&buffer, uploadHash)
+
+	// Loop to write streamed data to temp file
+	for msg := first; ; {
+		if data := msg.GetData(); len(data) > 0 {
+			if err := checkUploadLimit(received+uint64(len(data)), maxSize); err != nil {
+				return nil, err
+			}
+			received += uint64(len(data))
+
+			// Videos of a format not allowed are rejected before any of their data is written
+			if !told {
+				if head = append(head, data...); !s.headComplete(head) {
+					data = nil
+				} else {
+					if format, err = s.detectFormat(head); err != nil {
+						return nil, err
+					}
+					data, head, told = head, nil, true
+				}
+			}
+
+			if _, err := w.Write(data); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to write to temp file: %v", err)
+			}
+
+			if opts.ExpectedSize > 0 && received > opts.ExpectedSize {
+				return nil, status.Errorf(codes.DataLoss, "received more than the expected %d bytes", opts.ExpectedSize)
+			}
+
+			if onChunk != nil {
+				if err := onChunk(received); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		msg, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Unknown, "failed to receive data: %v", err)
+		}
+
+		if msg.GetOptions() != nil {
+			return nil, status.Error(codes.InvalidArgument, "options must only be sent in the first message")
+		}
+	}
+
+	// The video ended before telling its format
+	if len(head) > 0 {
+		_, err := s.detectFormat(head)
+		return nil, err
+	}
+
+	if err := buffer.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to close temp file: %v", err)
+	}
+
+	// Don't run ffmpeg on a truncated or corrupted upload
+	if err := verifyUpload(opts, received, uploadHash.Sum(nil)); err != nil {
+		return nil, err
+	}
+
+	if buffer.file == nil {
+		return &upload{data: buffer.buf.Bytes(), size: received, format: format}, nil
+	}
+	return &upload{path: buffer.file.Name(), size: received, format: format}, nil
+}
+
+// writeTempFile writes data to a new temp file in dir and returns its path.
+func writeTempFile(dir string, data []byte) (string, error) {
+	f, err := os.CreateTemp(dir, "input-*")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// spillBuffer keeps the data written to it in memory until it grows beyond limit bytes,
+// then moves it to a temp file in dir.
+type spillBuffer struct {
+	dir   string
+	limit int
+	buf   bytes.Buffer
+	file  *os.File // Set once spilled
+}
+
+func (b *spillBuffer) Write(p []byte) (int, error) {
+	if b.file == nil && b.buf.Len()+len(p) > b.limit {
+		if err := b.spill(); err != nil {
+			return 0, err
+		}
+	}
+
+	if b.file != nil {
+		return b.file.Write(p)
+	}
+	return b.buf.Write(p)
+}
+
+// spill moves the data written so far to a temp file, where the following writes go.
+func (b *spillBuffer) spill() error {
+	f, err := os.CreateTemp(b.dir, "input-*")
+	if err != nil {
+		return err
+	}
+	b.file = f
+
+	if _, err := f.Write(b.buf.Bytes()); err != nil {
+		return err
+	}
+
+	b.buf = bytes.Buffer{}
+	return nil
+}
+
+// Close closes the temp file, if spilled.
+func (b *spillBuffer) Close() error {
+	if b.file == nil {
+		return nil
+	}
+	return b.file.Close()
+}
+
+// Remove removes the temp file, if spilled.
+func (b *spillBuffer) Remove() {
+	if b.file != nil {
+		os.Remove(b.file.Name())
+	}
+}
+
+// validateIntegrityOptions checks the upload checksum and size announced by the client.
+func validateIntegrityOptions(opts *apiv1.ExtractOptions) error {
+	if opts.ExpectedSha256 == "" {
+		return nil
+	}
+
+	if checksum, err := hex.DecodeString(opts.ExpectedSha256); err != nil || len(checksum) != sha256.Size {
+		return status.Errorf(codes.InvalidArgument, "invalid expected sha256 %q: must be %d hex-encoded bytes", opts.ExpectedSha256, sha256.Size)
+	}
+	return nil
+}
+
+// verifyUpload checks the received upload against the size and checksum announced by the client.
+func verifyUpload(opts *apiv1.ExtractOptions, size uint64, checksum []byte) error {
+	if opts.ExpectedSize > 0 && size != opts.ExpectedSize {
+		return status.Errorf(codes.DataLoss, "received %d bytes, expected %d", size, opts.ExpectedSize)
+	}
+
+	if opts.ExpectedSha256 == "" {
+		return nil
+	}
+
+	if expected, _ := hex.DecodeString(opts.ExpectedSha256); !bytes.Equal(expected, checksum) {
+		return status.Errorf(codes.DataLoss, "upload sha256 %x does not match the expected %s", checksum, opts.ExpectedSha256)
+	}
+	return nil
+}
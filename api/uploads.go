@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/uploads"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *GRPCServer) CreateUploadSession(ctx context.Context, req *apiv1.CreateUploadSessionRequest) (*apiv1.UploadSession, error) {
+	session, err := s.uploads.Create(caller(ctx), req.ExpectedSize, s.uploadLimit(ctx))
+	if err != nil {
+		return nil, uploadSessionError(err)
+	}
+	return newUploadSession(session), nil
+}
+
+func (s *GRPCServer) GetUploadSession(ctx context.Context, req *apiv1.GetUploadSessionRequest) (*apiv1.UploadSession, error) {
+	session, err := s.callerSession(ctx, req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+	return newUploadSession(session), nil
+}
+
+func (s *GRPCServer) UploadChunks(stream apiv1.AudioStripper_UploadChunksServer) error {
+	var (
+		session *uploads.Session
+		id      string
+		head    []byte // The start of the video, held back until it tells its format
+	)
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Unknown, "failed to receive data: %v", err)
+		}
+
+		if id != "" && chunk.SessionId != id {
+			return status.Error(codes.InvalidArgument, "all chunks of a stream must be sent to the same session")
+		}
+
+		if id == "" {
+			if _, err := s.callerSession(stream.Context(), chunk.SessionId); err != nil {
+				return err
+			}
+			id = chunk.SessionId
+		}
+
+		data, offset := chunk.Data, chunk.Offset
+
+		// Videos of a format not allowed are rejected before any of their data is written
+		if len(data) > 0 && (offset == 0 || head != nil && offset == uint64(len(head))) {
+			if head = append(head, data...); !s.headComplete(head) {
+				continue
+			}
+
+			if _, err := s.detectFormat(head); err != nil {
+				return err
+			}
+			data, offset, head = head, 0, nil
+		}
+
+		// Every chunk is committed as soon as it is written, a dropped stream loses none of the previous ones
+		if session, err = s.uploads.Write(id, offset, data); err != nil {
+			return uploadSessionError(err)
+		}
+	}
+
+	// The stream ended before the video told its format, which is checked once the upload is complete
+	if head != nil {
+		var err error
+		if session, err = s.uploads.Write(id, 0, head); err != nil {
+			return uploadSessionError(err)
+		}
+	}
+
+	if session == nil {
+		return status.Error(codes.InvalidArgument, "no chunk received")
+	}
+
+	if err := stream.SendAndClose(newUploadSession(session)); err != nil {
+		return status.Errorf(codes.Internal, "failed to send upload session to client: %s", err)
+	}
+	return nil
+}
+
+// callerSession returns the upload session with the given ID if it belongs to the client of the RPC.
+// The sessions of other clients are reported as not found, not to tell they exist.
+func (s *GRPCServer) callerSession(ctx context.Context, id string) (*uploads.Session, error) {
+	session, err := s.uploads.Get(id)
+	if err != nil {
+		return nil, uploadSessionError(err)
+	}
+
+	if session.Owner != caller(ctx) {
+		return nil, uploadSessionError(fmt.Errorf("%w: %s", uploads.ErrNotFound, id))
+	}
+	return session, nil
+}
+
+// uploadSessionError converts an error of the upload session manager into a gRPC status.
+func uploadSessionError(err error) error {
+	switch {
+	case errors.Is(err, uploads.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, uploads.ErrOffsetMismatch), errors.Is(err, uploads.ErrIncomplete):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, uploads.ErrTooLarge):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, uploads.ErrLimitExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "upload failed: %v", err)
+	}
+}
+
+// newUploadSession converts an upload session into its API representation.
+func newUploadSession(session *uploads.Session) *apiv1.UploadSession {
+	return &apiv1.UploadSession{
+		SessionId:       session.ID,
+		CommittedOffset: session.Offset,
+		ExpectedSize:    session.ExpectedSize,
+		MaxSize:         session.MaxSize,
+		ExpiresAt:       timestamppb.New(session.ExpiresAt),
+	}
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUploadSession(t *testing.T) {
+	video := "videoDataChunk1videoDataChunk2"
+	checksum := sha256.Sum256([]byte(video))
+
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			data, err := os.ReadFile(in.FilePath)
+			assert.NoError(t, err)
+			assert.Equal(t, video, string(data))
+
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	session, err := client.CreateUploadSession(context.TODO(), &apiv1.CreateUploadSessionRequest{ExpectedSize: uint64(len(video))})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, session.SessionId)
+	assert.Zero(t, session.CommittedOffset)
+
+	// The first connection drops after the first chunk
+	got, err := uploadChunksHelper(t, client, &apiv1.UploadChunk{SessionId: session.SessionId, Offset: 0, Data: []byte(video[:15])})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(15), got.CommittedOffset)
+
+	// The session can't be used before it is complete
+	_, err = extractAudioHelper(t, client, optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100, UploadSessionId: session.SessionId}))
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Resending the chunk is rejected
+	_, err = uploadChunksHelper(t, client, &apiv1.UploadChunk{SessionId: session.SessionId, Offset: 0, Data: []byte(video[:15])})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Resume from the committed offset
+	got, err = client.GetUploadSession(context.TODO(), &apiv1.GetUploadSessionRequest{SessionId: session.SessionId})
+	require.NoError(t, err)
+
+	_, err = uploadChunksHelper(t, client, &apiv1.UploadChunk{SessionId: session.SessionId, Offset: got.CommittedOffset, Data: []byte(video[15:])})
+	require.NoError(t, err)
+
+	audio, err := extractAudioHelper(t, client, optionsMsg(&apiv1.ExtractOptions{
+		SampleRateHz:    44100,
+		UploadSessionId: session.SessionId,
+		ExpectedSha256:  hex.EncodeToString(checksum[:]),
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, video, string(audio))
+
+	// The session is consumed by the extraction
+	_, err = client.GetUploadSession(context.TODO(), &apiv1.GetUploadSessionRequest{SessionId: session.SessionId})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUploadSessionInvalidRequests(t *testing.T) {
+	server, lis := makeGRPCServerHelper(t, &mockAudioStripperService{})
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	session, err := client.CreateUploadSession(context.TODO(), &apiv1.CreateUploadSessionRequest{ExpectedSize: 5})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		chunk        *apiv1.UploadChunk
+		expectedCode codes.Code
+	}{
+		{
+			name:         "unknown session",
+			chunk:        &apiv1.UploadChunk{SessionId: "missing", Data: []byte("video")},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "beyond the expected size",
+			chunk:        &apiv1.UploadChunk{SessionId: session.SessionId, Data: []byte("videoData")},
+			expectedCode: codes.OutOfRange,
+		},
+		{
+			name:         "gap in the upload",
+			chunk:        &apiv1.UploadChunk{SessionId: session.SessionId, Offset: 2, Data: []byte("deo")},
+			expectedCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := uploadChunksHelper(t, client, tc.chunk)
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+
+	t.Run("data sent along the session", func(t *testing.T) {
+		_, err := uploadChunksHelper(t, client, &apiv1.UploadChunk{SessionId: session.SessionId, Data: []byte("video")})
+		require.NoError(t, err)
+
+		_, err = extractAudioHelper(t, client,
+			optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100, UploadSessionId: session.SessionId}),
+			dataMsg("videoData"),
+		)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestUploadSessionOwnership(t *testing.T) {
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+	}
+
+	server, lis := makeGRPCServerHelper(t, &mockService)
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	aliceCtx := metadata.AppendToOutgoingContext(context.TODO(), apiKeyHeader, "alice")
+	bobCtx := metadata.AppendToOutgoingContext(context.TODO(), apiKeyHeader, "bob")
+
+	session, err := client.CreateUploadSession(aliceCtx, &apiv1.CreateUploadSessionRequest{})
+	require.NoError(t, err)
+
+	uploadChunks := func(ctx context.Context, chunk *apiv1.UploadChunk) error {
+		stream, err := client.UploadChunks(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(chunk))
+
+		_, err = stream.CloseAndRecv()
+		return err
+	}
+
+	extractAudio := func(ctx context.Context) error {
+		stream, err := client.ExtractAudio(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100, UploadSessionId: session.SessionId})))
+		require.NoError(t, stream.CloseSend())
+
+		for {
+			if _, err := stream.Recv(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+
+	// Other clients can't tell the session exists
+	_, err = client.GetUploadSession(bobCtx, &apiv1.GetUploadSessionRequest{SessionId: session.SessionId})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetUploadSession(context.TODO(), &apiv1.GetUploadSessionRequest{SessionId: session.SessionId})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	err = uploadChunks(bobCtx, &apiv1.UploadChunk{SessionId: session.SessionId, Data: []byte("videoData")})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	require.NoError(t, uploadChunks(aliceCtx, &apiv1.UploadChunk{SessionId: session.SessionId, Data: []byte("videoData")}))
+
+	require.Equal(t, codes.NotFound, status.Code(extractAudio(bobCtx)))
+
+	// The session is still there for the client that created it
+	got, err := client.GetUploadSession(aliceCtx, &apiv1.GetUploadSessionRequest{SessionId: session.SessionId})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len("videoData")), got.CommittedOffset)
+
+	require.NoError(t, extractAudio(aliceCtx))
+}
+
+func uploadChunksHelper(t *testing.T, client apiv1.AudioStripperClient, chunks ...*apiv1.UploadChunk) (*apiv1.UploadSession, error) {
+	t.Helper()
+
+	stream, err := client.UploadChunks(context.TODO())
+	require.NoError(t, err)
+
+	for _, chunk := range chunks {
+		require.NoError(t, stream.Send(chunk))
+	}
+	return stream.CloseAndRecv()
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/alesr/audiostrippersvc/internal/workers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// retryAfterHeader is the trailer telling clients rejected for lack of workers how many seconds to wait before retrying.
+const retryAfterHeader = "retry-after"
+
+// WithWorkers runs the extractions on the workers of pool, rejecting the requests with RESOURCE_EXHAUSTED
+// when its queue is full. Rejected clients are told to retry after retryAfter.
+// Extractions run as soon as they are received otherwise.
+func WithWorkers(pool *workers.Pool, retryAfter time.Duration) Option {
+	return func(s *GRPCServer) {
+		s.workers = pool
+		s.retryAfter = retryAfter
+	}
+}
+
+// acquireWorker waits for a worker to run the extraction of the stream on, and returns the function releasing it.
+func (s *GRPCServer) acquireWorker(stream grpc.ServerStream) (release func(), err error) {
+	if s.workers == nil {
+		return func() {}, nil
+	}
+
+	release, err = s.workers.Acquire(stream.Context())
+	if errors.Is(err, workers.ErrQueueFull) {
+		stats := s.workers.Stats()
+		s.logger.Warn("Extraction queue full, rejecting request",
+			slog.Int("running", stats.Running),
+			slog.Int("queued", stats.Queued),
+		)
+		return nil, s.queueFullError(stream, "extractions")
+	}
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return release, nil
+}
+
+// queueFullError tells the client of the stream to retry after a while, the queue of the work it requested being full.
+func (s *GRPCServer) queueFullError(stream grpc.ServerStream, work string) error {
+	seconds := max(1, int(s.retryAfter.Round(time.Second).Seconds()))
+	stream.SetTrailer(metadata.Pairs(retryAfterHeader, strconv.Itoa(seconds)))
+
+	return status.Errorf(codes.ResourceExhausted, "too many %s in progress, retry in %d seconds", work, seconds)
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/app/jobs"
+	"github.com/alesr/audiostrippersvc/internal/workers"
+)
+
+func TestExtractAudioWorkers(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			started <- struct{}{}
+			<-release
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+	}
+
+	pool := workers.NewPool(1, 1)
+
+	server, lis := makeGRPCServerHelper(t, &mockService, WithWorkers(pool, 2500*time.Millisecond))
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	// The first extraction takes the only worker, the second one waits for it
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := extractAudioHelper(t, client, optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100}), dataMsg("videoData"))
+			done <- err
+		}()
+	}
+
+	<-started
+
+	require.Eventually(t, func() bool {
+		return pool.Stats().Queued == 1
+	}, time.Second, time.Millisecond)
+
+	// The third one is rejected
+	stream, err := client.ExtractAudio(context.TODO())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100})))
+	require.NoError(t, stream.Send(dataMsg("videoData")))
+	require.NoError(t, stream.CloseSend())
+
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	require.NotEqual(t, io.EOF, err)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"3"}, stream.Trailer().Get(retryAfterHeader))
+
+	// Both accepted extractions go through once the worker is released
+	close(release)
+	<-started
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, <-done)
+	}
+	assert.Equal(t, workers.Stats{Workers: 1}, pool.Stats())
+}
+
+func TestSubmitJobWorkers(t *testing.T) {
+	release := make(chan struct{})
+
+	mockService := mockAudioStripperService{
+		ExtractAudioFunc: func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+			<-release
+			return &audiostripper.ExtractAudioOutput{FilePath: in.FilePath}, nil
+		},
+	}
+
+	pool := workers.NewPool(1, 1)
+	jobManager := jobs.NewManager(noopLogger(), &mockService, jobs.WithWorkers(pool), jobs.WithMaxQueued(1))
+
+	server, lis := makeGRPCServerWithJobsHelper(t, &mockService, jobManager, WithWorkers(pool, 2500*time.Millisecond))
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	// The first job takes the only worker, the second one waits for it
+	running := submitJobHelper(t, client, optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100}), dataMsg("videoData"))
+
+	require.Eventually(t, func() bool {
+		return pool.Stats().Running == 1
+	}, time.Second, time.Millisecond)
+
+	queued := submitJobHelper(t, client, optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100}), dataMsg("videoData"))
+
+	// The third one is rejected
+	stream, err := client.SubmitJob(context.TODO())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(optionsMsg(&apiv1.ExtractOptions{SampleRateHz: 44100})))
+	require.NoError(t, stream.Send(dataMsg("videoData")))
+
+	_, err = stream.CloseAndRecv()
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"3"}, stream.Trailer().Get(retryAfterHeader))
+
+	list, err := client.ListJobs(context.TODO(), &apiv1.ListJobsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.Jobs, 2)
+
+	// Both accepted jobs go through once the worker is released
+	close(release)
+
+	for _, job := range []*apiv1.Job{running, queued} {
+		assert.Eventually(t, func() bool {
+			got, err := client.GetJob(context.TODO(), &apiv1.GetJobRequest{JobId: job.Id})
+			return err == nil && got.State == apiv1.JobState_JOB_STATE_SUCCEEDED
+		}, time.Second, 10*time.Millisecond)
+	}
+}
+
+func TestProbeMediaWorkers(t *testing.T) {
+	var probed int
+
+	mockService := mockAudioStripperService{
+		ProbeMediaFunc: func(ctx context.Context, in *audiostripper.ProbeMediaInput) (*audiostripper.MediaInfo, error) {
+			probed++
+			return &audiostripper.MediaInfo{FormatName: "matroska,webm"}, nil
+		},
+	}
+
+	pool := workers.NewPool(1, 0)
+
+	server, lis := makeGRPCServerHelper(t, &mockService, WithWorkers(pool, 2500*time.Millisecond))
+	defer server.Stop()
+
+	client := makeGRPCClientHelper(t, lis)
+
+	// The only worker is busy and nothing may wait for it, so the probe is rejected
+	release, err := pool.Acquire(context.TODO())
+	require.NoError(t, err)
+
+	stream, err := client.ProbeMedia(context.TODO())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(dataMsg("videoData")))
+
+	_, err = stream.CloseAndRecv()
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"3"}, stream.Trailer().Get(retryAfterHeader))
+	assert.Zero(t, probed)
+
+	// It goes through once the worker is released
+	release()
+
+	stream, err = client.ProbeMedia(context.TODO())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(dataMsg("videoData")))
+
+	got, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, "matroska,webm", got.FormatName)
+	assert.Equal(t, 1, probed)
+}
@@ -1,16 +1,29 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
+	"fmt"
 	"log/slog"
 	"net"
 	"os"
-	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
 
-	"github.com/alesr/audiostripper"
 	"github.com/alesr/audiostrippersvc/api"
 	apiv1 "github.com/alesr/audiostrippersvc/api/proto/audiostrippersvc/v1"
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/app/jobs"
+	"github.com/alesr/audiostrippersvc/internal/app/uploads"
+	"github.com/alesr/audiostrippersvc/internal/ffmpeg"
+	"github.com/alesr/audiostrippersvc/internal/janitor"
+	"github.com/alesr/audiostrippersvc/internal/jobstore"
+	"github.com/alesr/audiostrippersvc/internal/sniff"
+	"github.com/alesr/audiostrippersvc/internal/workers"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
@@ -19,30 +32,66 @@ const (
 	grpcPort string = ":50051"
 	certPath string = "/etc/ssl/mycerts/cert.pem"
 	keyPath  string = "/etc/ssl/mycerts/key.pem"
-)
 
-var (
-	version string
-	useSSL  bool
+	uploadSessionTTL = 24 * time.Hour
 
-	extractCmd audiostripper.ExtractCmd = func(params *audiostripper.ExtractCmdParams) error {
-		cmd := exec.Command(
-			"ffmpeg", "-y", "-i", params.InputFile, "-vn", "-acodec", "pcm_s16le", "-ar", params.SampleRate,
-			"-ac", "2", "-b:a", "32k", params.OutputFile,
-		)
+	// retryAfter is the delay suggested to the clients turned away by a full wait queue
+	retryAfter = 5 * time.Second
+)
 
-		cmd.Stderr = params.Stderr
-		return cmd.Run()
-	}
+var (
+	version       string
+	useSSL        bool
+	dataDir       string
+	maxUploadSize uint64
+	uploadLimits  string
+	workDir       string
+	orphanAge     time.Duration
+	resultTTL     time.Duration
+	jobRetention  time.Duration
+	numWorkers    int
+	maxQueue      int
+	timeout       time.Duration
+	maxDuration   time.Duration
+	formats       string
+	ffmpegLimits  ffmpeg.Limits
 )
 
 func main() {
 	flag.BoolVar(&useSSL, "ssl", false, "Use SSL for the gRPC server")
+	flag.StringVar(&dataDir, "data-dir", "data", "Directory the jobs and their files are stored in")
+	flag.Uint64Var(&maxUploadSize, "max-upload-size", 2<<30, "Maximum size of an uploaded video in bytes, 0 for no limit")
+	flag.StringVar(&uploadLimits, "upload-limits", "", "JSON file mapping API keys to their maximum upload size in bytes")
+	flag.StringVar(&workDir, "work-dir", filepath.Join(os.TempDir(), "audiostrippersvc"), "Directory the uploads and extracted audio are written to while handled")
+	flag.DurationVar(&orphanAge, "orphan-age", 2*uploadSessionTTL, "Age past which files left in the work directory are removed, must exceed the upload session TTL")
+	flag.DurationVar(&resultTTL, "result-ttl", 24*time.Hour, "How long the result of a job can be downloaded once it succeeded, 0 to keep it")
+	flag.DurationVar(&jobRetention, "job-retention", 7*24*time.Hour, "How long finished jobs are kept once they last changed, 0 to keep them")
+	flag.IntVar(&numWorkers, "workers", runtime.NumCPU(), "Maximum number of extractions running at once")
+	flag.IntVar(&maxQueue, "max-queue", 2*runtime.NumCPU(), "Maximum number of extractions, and of jobs, waiting for a worker before requests are rejected")
+	flag.DurationVar(&timeout, "timeout", 30*time.Minute, "Maximum processing time of an extraction, 0 for no limit")
+	flag.DurationVar(&maxDuration, "max-duration", 4*time.Hour, "Maximum duration of the media audio is extracted from, 0 for no limit")
+	flag.StringVar(&formats, "allowed-formats", strings.Join(sniff.Formats, ","), "Comma-separated container formats of the videos accepted, empty to accept any")
+	flag.DurationVar(&ffmpegLimits.CPUTime, "ffmpeg-cpu-time", time.Hour, "Maximum CPU time of an ffmpeg process, 0 for no limit")
+	flag.Uint64Var(&ffmpegLimits.Memory, "ffmpeg-memory", 4<<30, "Maximum address space of an ffmpeg process in bytes, 0 for no limit")
+	flag.Uint64Var(&ffmpegLimits.FileSize, "ffmpeg-file-size", 4<<30, "Maximum size of a file written by ffmpeg in bytes, 0 for no limit")
+	flag.Uint64Var(&ffmpegLimits.OpenFiles, "ffmpeg-open-files", 256, "Maximum number of files an ffmpeg process opens, 0 for no limit")
 	flag.Parse()
 
 	logger := makeLogger()
 	logger.Info("Running Audiostripper")
 
+	apiKeyMaxUploadSizes, err := loadUploadLimits(uploadLimits)
+	if err != nil {
+		logger.Error("Could not load upload limits", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	allowedFormats, err := parseAllowedFormats(formats)
+	if err != nil {
+		logger.Error("Invalid allowed formats", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
 	var serverOpts []grpc.ServerOption
 
 	if useSSL {
@@ -56,9 +105,77 @@ func main() {
 
 	grpcServer := grpc.NewServer(serverOpts...)
 
+	jobsDir := filepath.Join(dataDir, "jobs")
+
+	if err := os.MkdirAll(jobsDir, 0o700); err != nil {
+		logger.Error("Could not create data directory", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(workDir, 0o700); err != nil {
+		logger.Error("Could not create work directory", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Remove the files a previous run left behind
+	sweepWorkDir(logger)
+
+	store, err := jobstore.Open(filepath.Join(dataDir, "jobs.db"))
+	if err != nil {
+		logger.Error("Could not open job store", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	if ffmpegLimits != (ffmpeg.Limits{}) && runtime.GOOS != "linux" {
+		logger.Warn("ffmpeg resource limits are only enforced on Linux, ffmpeg runs unlimited", slog.String("os", runtime.GOOS))
+	}
+
+	stripper := audiostripper.New(ffmpegLimits.Extract, ffmpegLimits.Probe,
+		audiostripper.WithTimeout(timeout),
+		audiostripper.WithMaxDuration(maxDuration),
+	)
+
+	// Extractions requested directly and run as jobs share the same workers
+	pool := workers.NewPool(numWorkers, maxQueue)
+
+	jobManager := jobs.NewManager(logger, stripper,
+		jobs.WithStore(store),
+		jobs.WithDir(jobsDir),
+		jobs.WithResultTTL(resultTTL),
+		jobs.WithRetention(jobRetention),
+		jobs.WithWorkers(pool),
+		jobs.WithMaxQueued(maxQueue),
+	)
+
+	// Pick up the jobs that were in flight when the service last stopped
+	if err := jobManager.Recover(); err != nil {
+		logger.Error("Could not recover jobs", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	uploadSessions := uploads.NewManager(workDir, uploadSessionTTL)
+
+	// Discard the partial uploads of the sessions clients gave up on, the results nobody downloaded in time,
+	// the jobs past their retention, and whatever leaked into the work directory
+	go func() {
+		for range time.Tick(time.Minute) {
+			uploadSessions.RemoveExpired()
+			jobManager.RemoveExpired()
+			sweepWorkDir(logger)
+			logWorkers(logger, pool)
+		}
+	}()
+
 	grpcServer.RegisterService(
 		&apiv1.AudioStripper_ServiceDesc,
-		api.NewGRPCServer(logger, audiostripper.New(extractCmd)),
+		api.NewGRPCServer(logger, stripper, jobManager, uploadSessions,
+			api.WithMaxUploadSize(maxUploadSize),
+			api.WithAPIKeyMaxUploadSizes(apiKeyMaxUploadSizes),
+			api.WithWorkDir(workDir),
+			api.WithWorkers(pool, retryAfter),
+			api.WithAllowedFormats(allowedFormats),
+		),
 	)
 
 	logger.Info("Starting gRPC server")
@@ -86,6 +203,63 @@ func main() {
 	grpcServer.GracefulStop()
 }
 
+// sweepWorkDir removes the files older than orphanAge from the work directory.
+func sweepWorkDir(logger *slog.Logger) {
+	removed, err := janitor.Sweep(workDir, orphanAge)
+	if err != nil {
+		logger.Error("Failed to sweep work directory", slog.String("error", err.Error()))
+	}
+
+	for _, path := range removed {
+		logger.Warn("Removed orphaned file", slog.String("file", path))
+	}
+}
+
+// logWorkers logs the usage of the worker pool and the depth of its queue.
+func logWorkers(logger *slog.Logger, pool *workers.Pool) {
+	stats := pool.Stats()
+
+	logger.Info("Workers",
+		slog.Int("workers", stats.Workers),
+		slog.Int("running", stats.Running),
+		slog.Int("queued", stats.Queued),
+		slog.Int("waiting", stats.Waiting),
+	)
+}
+
+// parseAllowedFormats parses the comma-separated list of allowed container formats, nil when empty.
+func parseAllowedFormats(list string) ([]string, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	formats := strings.Split(list, ",")
+	for _, format := range formats {
+		if !slices.Contains(sniff.Formats, format) {
+			return nil, fmt.Errorf("unknown format %q, must be one of %v", format, sniff.Formats)
+		}
+	}
+	return formats, nil
+}
+
+// loadUploadLimits reads the maximum upload sizes by API key from the JSON file at path, if any.
+func loadUploadLimits(path string) (map[string]uint64, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var limits map[string]uint64
+	if err := json.Unmarshal(data, &limits); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %s", path, err)
+	}
+	return limits, nil
+}
+
 func makeLogger() *slog.Logger {
 	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		AddSource: true,
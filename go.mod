@@ -3,8 +3,9 @@ module github.com/alesr/audiostrippersvc
 go 1.21.0
 
 require (
-	github.com/alesr/audiostripper v0.0.0-20230828105950-de355ed9b475
 	github.com/stretchr/testify v1.8.4
+	go.etcd.io/bbolt v1.3.10
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230822172742-b8732ec3820d
 	google.golang.org/grpc v1.57.0
 	google.golang.org/protobuf v1.31.0
 )
@@ -16,6 +17,5 @@ require (
 	golang.org/x/net v0.14.0 // indirect
 	golang.org/x/sys v0.11.0 // indirect
 	golang.org/x/text v0.12.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20230822172742-b8732ec3820d // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
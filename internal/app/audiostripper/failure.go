@@ -0,0 +1,116 @@
+package audiostripper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidData is the failure of a command given media it could not demux or decode.
+	ErrInvalidData = errors.New("invalid media data")
+
+	// ErrNoAudio is the failure of a command given media without audio streams.
+	ErrNoAudio = errors.New("no audio stream")
+
+	// ErrUnsupportedCodec is the failure of a command given media encoded with a codec it does not support.
+	ErrUnsupportedCodec = errors.New("unsupported codec")
+
+	// ErrDiskFull is the failure of a command running out of disk space to write its output.
+	ErrDiskFull = errors.New("disk full")
+
+	// ErrResourceLimit is the failure of a command killed for going beyond its CPU time or file size limit.
+	ErrResourceLimit = errors.New("resource limit exceeded")
+)
+
+const (
+	// maxExcerptLines and maxExcerptSize bound the stderr excerpt of a CmdError.
+	maxExcerptLines = 5
+	maxExcerptSize  = 1024
+)
+
+// failurePatterns are the ffmpeg and ffprobe log messages telling the cause of their failure, by cause.
+// They are looked for in order, the first one found wins.
+var failurePatterns = []struct {
+	kind     error
+	messages []string
+}{
+	{kind: ErrDiskFull, messages: []string{"No space left on device"}},
+	{kind: ErrNoAudio, messages: []string{"does not contain any stream", "matches no streams"}},
+	{kind: ErrUnsupportedCodec, messages: []string{"Decoder (codec", "Unknown decoder", "unsupported codec", "not currently supported"}},
+	{kind: ErrInvalidData, messages: []string{"Invalid data found when processing input", "moov atom not found", "EBML header parsing failed", "could not find codec parameters"}},
+}
+
+// CmdError is the failure of a command, along with its cause as told by what it wrote to stderr.
+type CmdError struct {
+	Cmd    string // Name of the command
+	Err    error  // The error the command ended with
+	Kind   error  // One of ErrInvalidData, ErrNoAudio, ErrUnsupportedCodec, ErrDiskFull or ErrResourceLimit, nil when not recognized
+	Stderr string // The last lines written to stderr
+}
+
+func (e *CmdError) Error() string {
+	if e.Kind == nil || errors.Is(e.Err, e.Kind) {
+		return fmt.Sprintf("could not run %s command: %s", e.Cmd, e.Err)
+	}
+	return fmt.Sprintf("could not run %s command: %s: %s", e.Cmd, e.Kind, e.Err)
+}
+
+func (e *CmdError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// newCmdError returns the failure of the named command which wrote stderr.
+// The paths of the files the command was run on are replaced with their role in the stderr excerpt,
+// the paths on the server being none of the clients business. They are replaced before the excerpt is cut,
+// which could otherwise leave a partial path behind.
+func newCmdError(name string, err error, stderr []byte, files *strings.Replacer) *CmdError {
+	cmdErr := CmdError{
+		Cmd:    name,
+		Err:    err,
+		Stderr: excerpt([]byte(files.Replace(string(stderr)))),
+	}
+
+	// Killed by the kernel, the command had no chance to tell why
+	if errors.Is(err, ErrResourceLimit) {
+		cmdErr.Kind = ErrResourceLimit
+		return &cmdErr
+	}
+
+	for _, p := range failurePatterns {
+		for _, msg := range p.messages {
+			if bytes.Contains(stderr, []byte(msg)) {
+				cmdErr.Kind = p.kind
+				return &cmdErr
+			}
+		}
+	}
+	return &cmdErr
+}
+
+// excerpt returns the last non-empty lines of stderr, where the commands report the error they stopped on.
+func excerpt(stderr []byte) string {
+	var lines []string
+
+	for _, line := range strings.Split(string(stderr), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) > maxExcerptLines {
+		lines = lines[len(lines)-maxExcerptLines:]
+	}
+
+	s := strings.Join(lines, "\n")
+	if len(s) > maxExcerptSize {
+		s = s[len(s)-maxExcerptSize:]
+	}
+
+	// The cut may fall within a character, and the logs may quote file names that are not UTF-8 to begin with
+	return strings.ToValidUTF8(s, "")
+}
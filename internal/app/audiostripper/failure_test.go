@@ -0,0 +1,122 @@
+package audiostripper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stderr   string
+		expected error
+	}{
+		{
+			name:     "invalid data",
+			stderr:   "[mov,mp4,m4a,3gp,3g2,mj2 @ 0x55d5c1e0] moov atom not found\n/tmp/video.mp4: Invalid data found when processing input\n",
+			expected: ErrInvalidData,
+		},
+		{
+			name:     "no audio stream",
+			stderr:   "Output #0, wav, to '/tmp/video.wav':\nOutput file #0 does not contain any stream\n",
+			expected: ErrNoAudio,
+		},
+		{
+			name:     "stream selection matching no stream",
+			stderr:   "Stream map '0:a:1' matches no streams.\nTo ignore this, add a trailing '?' to the map.\n",
+			expected: ErrNoAudio,
+		},
+		{
+			name:     "unsupported codec",
+			stderr:   "Decoder (codec none) not found for input stream #0:1\n",
+			expected: ErrUnsupportedCodec,
+		},
+		{
+			name:     "disk full",
+			stderr:   "[wav @ 0x5581] Error writing trailer of /tmp/video.wav: No space left on device\n",
+			expected: ErrDiskFull,
+		},
+		{
+			name:   "unknown failure",
+			stderr: "Conversion failed!\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newCmdError("extractor", errors.New("exit status 1"), []byte(tc.stderr), strings.NewReplacer())
+
+			assert.Equal(t, tc.expected, err.Kind)
+			if tc.expected != nil {
+				assert.ErrorIs(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNewCmdErrorResourceLimit(t *testing.T) {
+	killed := fmt.Errorf("%w: %w", ErrResourceLimit, errors.New("signal: CPU time limit exceeded"))
+
+	// Whatever the command logged before being killed, the limit is the cause
+	err := newCmdError("extractor", killed, []byte("Invalid data found when processing input\n"), strings.NewReplacer())
+
+	assert.Equal(t, ErrResourceLimit, err.Kind)
+	assert.ErrorIs(t, err, ErrResourceLimit)
+	assert.Equal(t, "could not run extractor command: resource limit exceeded: signal: CPU time limit exceeded", err.Error())
+}
+
+func TestNewCmdErrorLongPath(t *testing.T) {
+	path := "/tmp/audiostrippersvc/" + strings.Repeat("p", 100) + ".mp4"
+	stderr := path + ": " + strings.Repeat("e", maxExcerptSize-20)
+
+	err := newCmdError("extractor", errors.New("exit status 1"), []byte(stderr), strings.NewReplacer(path, "input"))
+
+	// Cutting the excerpt first would have left the end of the path behind
+	assert.NotContains(t, err.Stderr, "ppp")
+	assert.True(t, strings.HasPrefix(err.Stderr, "input: "))
+}
+
+func TestExcerpt(t *testing.T) {
+	t.Run("last lines", func(t *testing.T) {
+		got := excerpt([]byte("1\n2\n\n3\n  4  \n5\n6\n"))
+		assert.Equal(t, "2\n3\n4\n5\n6", got)
+	})
+
+	t.Run("long lines", func(t *testing.T) {
+		got := excerpt([]byte(strings.Repeat("é", maxExcerptSize)))
+		assert.LessOrEqual(t, len(got), maxExcerptSize)
+		assert.True(t, strings.HasSuffix(got, "éé"))
+		assert.False(t, strings.ContainsRune(got, '�'))
+	})
+}
+
+func TestExtractAudioCmdError(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "upload-123.mp4")
+
+	cmdMock := func(params *ExtractCmdParams) error {
+		_, err := params.Stderr.Write([]byte(params.InputFile + ": Invalid data found when processing input"))
+		assert.NoError(t, err)
+		return errors.New("exit status 1")
+	}
+
+	probeMock := func(params *ProbeCmdParams) error {
+		t.Error("the output of a failed extraction must not be probed")
+		return nil
+	}
+
+	_, err := New(cmdMock, probeMock).ExtractAudio(context.TODO(), &ExtractAudioInput{FilePath: input, SampleRate: 44100})
+	require.ErrorIs(t, err, ErrInvalidData)
+
+	var cmdErr *CmdError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, "extractor", cmdErr.Cmd)
+	assert.Equal(t, "input: Invalid data found when processing input", cmdErr.Stderr)
+}
@@ -0,0 +1,155 @@
+package audiostripper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stream types reported by the probe command.
+const (
+	StreamTypeAudio      = "audio"
+	StreamTypeVideo      = "video"
+	StreamTypeSubtitle   = "subtitle"
+	StreamTypeData       = "data"
+	StreamTypeAttachment = "attachment"
+)
+
+type (
+	// MediaInfo describes a media file.
+	MediaInfo struct {
+		FormatName string
+		Duration   time.Duration
+		BitRate    int64 // In bits per second
+		Streams    []StreamInfo
+	}
+
+	// StreamInfo describes a single stream of a media file.
+	StreamInfo struct {
+		Index      int // Index of the stream in the file
+		Type       string
+		Codec      string
+		SampleRate int // In Hz, audio streams only
+		Channels   int // Audio streams only
+		Language   string
+	}
+
+	// ProbeMediaInput defines the input for the ProbeMedia method.
+	ProbeMediaInput struct {
+		FilePath    string
+		InputFormat string // The ffmpeg demuxer of the file, empty to let the command probe it
+	}
+
+	// ProbeCmdParams defines the parameters of the probe command.
+	// The command writes the ffprobe JSON description of InputFile to Stdout.
+	ProbeCmdParams struct {
+		Context        context.Context // The command is killed once it is done
+		InputFile      string
+		InputFormat    string // Empty to let the command probe it
+		Stdout, Stderr io.Writer
+	}
+
+	// ProbeCmd is a function that runs the probe command.
+	ProbeCmd func(params *ProbeCmdParams) error
+)
+
+// AudioStreams returns the audio streams of the media, in file order.
+func (m *MediaInfo) AudioStreams() []StreamInfo {
+	var streams []StreamInfo
+	for _, s := range m.Streams {
+		if s.Type == StreamTypeAudio {
+			streams = append(streams, s)
+		}
+	}
+	return streams
+}
+
+// ffprobeOutput is the subset of `ffprobe -print_format json -show_format -show_streams` we rely on.
+type ffprobeOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+	Streams []struct {
+		Index      int    `json:"index"`
+		CodecType  string `json:"codec_type"`
+		CodecName  string `json:"codec_name"`
+		SampleRate string `json:"sample_rate"`
+		Channels   int    `json:"channels"`
+		Tags       struct {
+			Language string `json:"language"`
+		} `json:"tags"`
+	} `json:"streams"`
+}
+
+// ProbeMedia describes the container and streams of a media file.
+func (a *Audiostripper) ProbeMedia(ctx context.Context, in *ProbeMediaInput) (*MediaInfo, error) {
+	return a.probe(ctx, in.FilePath, in.InputFormat)
+}
+
+// probe runs the probe command on the file at path, of the given format unless empty.
+func (a *Audiostripper) probe(ctx context.Context, path, format string) (*MediaInfo, error) {
+	var stdout, stderr bytes.Buffer
+
+	params := ProbeCmdParams{
+		Context:     ctx,
+		InputFile:   path,
+		InputFormat: format,
+		Stdout:      &stdout,
+		Stderr:      &stderr,
+	}
+
+	if err := a.probeCmd(&params); err != nil {
+		return nil, cmdError(ctx, "probe", err, stderr.Bytes(), strings.NewReplacer(path, "input"))
+	}
+
+	info, err := parseProbeOutput(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("could not parse probe output: %s", err)
+	}
+	return info, nil
+}
+
+func parseProbeOutput(data []byte) (*MediaInfo, error) {
+	var out ffprobeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+
+	info := MediaInfo{
+		FormatName: out.Format.FormatName,
+		Duration:   parseSeconds(out.Format.Duration),
+	}
+
+	// Fields ffprobe cannot determine are reported as "N/A" or omitted, leave them zeroed
+	info.BitRate, _ = strconv.ParseInt(out.Format.BitRate, 10, 64)
+
+	for _, s := range out.Streams {
+		sampleRate, _ := strconv.Atoi(s.SampleRate)
+
+		info.Streams = append(info.Streams, StreamInfo{
+			Index:      s.Index,
+			Type:       s.CodecType,
+			Codec:      s.CodecName,
+			SampleRate: sampleRate,
+			Channels:   s.Channels,
+			Language:   s.Tags.Language,
+		})
+	}
+	return &info, nil
+}
+
+// parseSeconds parses a decimal number of seconds, as printed by ffprobe, into a duration.
+func parseSeconds(s string) time.Duration {
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,63 @@
+package audiostripper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeMedia(t *testing.T) {
+	stripper := New(
+		func(params *ExtractCmdParams) error {
+			t.Fatal("extract command must not run when probing")
+			return nil
+		},
+		func(params *ProbeCmdParams) error {
+			assert.Equal(t, "test.mp4", params.InputFile)
+			_, err := params.Stdout.Write([]byte(`{"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "2.000000"}}`))
+			return err
+		},
+	)
+
+	got, err := stripper.ProbeMedia(context.TODO(), &ProbeMediaInput{FilePath: "test.mp4"})
+	require.NoError(t, err)
+
+	assert.Equal(t, &MediaInfo{FormatName: "mov,mp4,m4a,3gp,3g2,mj2", Duration: 2 * time.Second}, got)
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	const ffprobeOutput = `{
+    "streams": [
+        {"index": 0, "codec_name": "h264", "codec_type": "video"},
+        {"index": 1, "codec_name": "aac", "codec_type": "audio", "sample_rate": "48000", "channels": 6, "tags": {"language": "eng"}},
+        {"index": 2, "codec_name": "opus", "codec_type": "audio", "sample_rate": "48000", "channels": 2, "tags": {"language": "por"}}
+    ],
+    "format": {"format_name": "matroska,webm", "duration": "62.500000", "bit_rate": "1205000"}
+}`
+
+	got, err := parseProbeOutput([]byte(ffprobeOutput))
+	require.NoError(t, err)
+
+	assert.Equal(t, &MediaInfo{
+		FormatName: "matroska,webm",
+		Duration:   62500 * time.Millisecond,
+		BitRate:    1205000,
+		Streams: []StreamInfo{
+			{Index: 0, Type: StreamTypeVideo, Codec: "h264"},
+			{Index: 1, Type: StreamTypeAudio, Codec: "aac", SampleRate: 48000, Channels: 6, Language: "eng"},
+			{Index: 2, Type: StreamTypeAudio, Codec: "opus", SampleRate: 48000, Channels: 2, Language: "por"},
+		},
+	}, got)
+
+	assert.Len(t, got.AudioStreams(), 2)
+}
+
+func TestParseProbeOutputUnknownValues(t *testing.T) {
+	got, err := parseProbeOutput([]byte(`{"format": {"format_name": "wav", "duration": "N/A", "bit_rate": "N/A"}}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, &MediaInfo{FormatName: "wav"}, got)
+}
@@ -0,0 +1,171 @@
+package audiostripper
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// progressKeys are the keys ffmpeg writes when run with -progress.
+var progressKeys = map[string]bool{
+	"bitrate":     true,
+	"total_size":  true,
+	"out_time_us": true,
+	"out_time_ms": true,
+	"out_time":    true,
+	"dup_frames":  true,
+	"drop_frames": true,
+	"speed":       true,
+	"progress":    true,
+}
+
+// durationRe matches the input duration ffmpeg logs, e.g. "  Duration: 00:01:02.03, start: ...".
+var durationRe = regexp.MustCompile(`^\s*Duration: (\d+):(\d{2}):(\d{2}(?:\.\d+)?)`)
+
+// outputAudioRe matches the audio stream of the output ffmpeg logs, e.g.
+// "  Stream #0:0: Audio: pcm_s16le ([1][0][0][0] / 0x0001), 44100 Hz, stereo, s16, 1411 kb/s".
+var outputAudioRe = regexp.MustCompile(`^\s*Stream #\d+:\d+.*?: Audio: (\w+).*?, (\d+) Hz, ([^,]+)`)
+
+// namedLayouts are the channel counts of the layouts ffmpeg names rather than numbers them, as in 5.1.
+var namedLayouts = map[string]int{
+	"mono":          1,
+	"stereo":        2,
+	"downmix":       2,
+	"quad":          4,
+	"hexagonal":     6,
+	"octagonal":     8,
+	"hexadecagonal": 16,
+}
+
+// Progress reports how far the extraction command got.
+type Progress struct {
+	OutTime  time.Duration // Position of the last extracted audio frame
+	Duration time.Duration // Duration of the input, zero when unknown
+}
+
+// Percent returns the completed percentage of the extraction, or zero when the input duration is unknown.
+func (p Progress) Percent() float64 {
+	if p.Duration <= 0 {
+		return 0
+	}
+	return min(100, float64(p.OutTime)/float64(p.Duration)*100)
+}
+
+// progressWriter parses the stderr of ffmpeg run with `-progress pipe:2`.
+// Progress blocks are reported to onProgress, every other line is passed through to w.
+// The duration of the progress is taken from the ffmpeg log unless set beforehand,
+// and the audio stream of the output from the description ffmpeg logs of it.
+type progressWriter struct {
+	w          io.Writer
+	onProgress func(Progress)
+	progress   Progress
+	output     bool        // Whether the log got to the description of the output
+	audio      *StreamInfo // The audio stream of the output, nil until logged
+	line       []byte
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	pw.line = append(pw.line, p...)
+
+	for {
+		i := bytes.IndexByte(pw.line, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+
+		if err := pw.handleLine(pw.line[:i+1]); err != nil {
+			return 0, err
+		}
+		pw.line = pw.line[i+1:]
+	}
+}
+
+// Flush passes any incomplete trailing line through to w.
+func (pw *progressWriter) Flush() error {
+	if len(pw.line) == 0 {
+		return nil
+	}
+
+	_, err := pw.w.Write(pw.line)
+	pw.line = nil
+	return err
+}
+
+func (pw *progressWriter) handleLine(line []byte) error {
+	key, value, ok := strings.Cut(strings.TrimSpace(string(line)), "=")
+	if ok && progressKeys[key] {
+		switch key {
+		case "out_time_us":
+			if us, err := strconv.ParseInt(value, 10, 64); err == nil {
+				pw.progress.OutTime = time.Duration(us) * time.Microsecond
+			}
+		case "progress":
+			if pw.onProgress != nil {
+				pw.onProgress(pw.progress)
+			}
+		}
+		return nil
+	}
+
+	if m := durationRe.FindSubmatch(line); m != nil && pw.progress.Duration == 0 {
+		pw.progress.Duration = parseTimestamp(string(m[1]), string(m[2]), string(m[3]))
+	}
+
+	// The streams of the input are described first, with the same syntax
+	if bytes.HasPrefix(line, []byte("Output #")) {
+		pw.output = true
+	}
+
+	if m := outputAudioRe.FindSubmatch(line); m != nil && pw.output && pw.audio == nil {
+		sampleRate, _ := strconv.Atoi(string(m[2]))
+
+		pw.audio = &StreamInfo{
+			Type:       StreamTypeAudio,
+			Codec:      string(m[1]),
+			SampleRate: sampleRate,
+			Channels:   channelCount(string(m[3])),
+		}
+	}
+
+	_, err := pw.w.Write(line)
+	return err
+}
+
+// channelCount returns the number of channels of a layout as ffmpeg names it, e.g. stereo, 5.1(side)
+// or 3 channels, zero when unknown.
+func channelCount(layout string) int {
+	layout, _, _ = strings.Cut(strings.TrimSpace(layout), "(")
+
+	if n, ok := namedLayouts[layout]; ok {
+		return n
+	}
+
+	if n, ok := strings.CutSuffix(layout, " channels"); ok {
+		count, _ := strconv.Atoi(n)
+		return count
+	}
+
+	// Main and low frequency channels
+	main, lfe, _ := strings.Cut(layout, ".")
+	m, err := strconv.Atoi(main)
+	if err != nil {
+		return 0
+	}
+	l, err := strconv.Atoi(lfe)
+	if err != nil {
+		return 0
+	}
+	return m + l
+}
+
+// parseTimestamp converts the components of an HH:MM:SS.ss timestamp into a duration.
+func parseTimestamp(hours, minutes, seconds string) time.Duration {
+	h, _ := strconv.Atoi(hours)
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.ParseFloat(seconds, 64)
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s*float64(time.Second))
+}
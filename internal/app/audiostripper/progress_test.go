@@ -0,0 +1,87 @@
+package audiostripper
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressWriter(t *testing.T) {
+	const ffmpegStderr = `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'input.mp4':
+  Duration: 00:00:10.00, start: 0.000000, bitrate: 1205 kb/s
+bitrate= 512.0kbits/s
+total_size=320078
+out_time_us=5000000
+out_time_ms=5000000
+out_time=00:00:05.000000
+speed=10.1x
+progress=continue
+Stream mapping:
+bitrate=1411.2kbits/s
+total_size=1764078
+out_time_us=10000000
+progress=end
+`
+
+	var (
+		stderr bytes.Buffer
+		got    []Progress
+	)
+
+	pw := progressWriter{
+		w:          &stderr,
+		onProgress: func(p Progress) { got = append(got, p) },
+	}
+
+	// Write in small pieces to exercise line reassembly
+	data := []byte(ffmpegStderr + "trailing")
+	for len(data) > 0 {
+		n := min(7, len(data))
+		_, err := pw.Write(data[:n])
+		require.NoError(t, err)
+		data = data[n:]
+	}
+	require.NoError(t, pw.Flush())
+
+	require.Equal(t, []Progress{
+		{OutTime: 5 * time.Second, Duration: 10 * time.Second},
+		{OutTime: 10 * time.Second, Duration: 10 * time.Second},
+	}, got)
+
+	assert.Equal(t, 50.0, got[0].Percent())
+	assert.Equal(t, 100.0, got[1].Percent())
+
+	// Progress lines are not kept in the log
+	assert.Equal(t, `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'input.mp4':
+  Duration: 00:00:10.00, start: 0.000000, bitrate: 1205 kb/s
+Stream mapping:
+trailing`, stderr.String())
+}
+
+func TestChannelCount(t *testing.T) {
+	testCases := []struct {
+		layout   string
+		expected int
+	}{
+		{layout: "mono", expected: 1},
+		{layout: "stereo", expected: 2},
+		{layout: "quad(side)", expected: 4},
+		{layout: "5.1", expected: 6},
+		{layout: "7.1(wide)", expected: 8},
+		{layout: "3 channels", expected: 3},
+		{layout: "unknown", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.layout, func(t *testing.T) {
+			assert.Equal(t, tc.expected, channelCount(tc.layout))
+		})
+	}
+}
+
+func TestProgressPercentUnknownDuration(t *testing.T) {
+	assert.Zero(t, Progress{OutTime: time.Second}.Percent())
+}
@@ -0,0 +1,520 @@
+package audiostripper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidInput is returned when the extraction input is not supported.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrOutOfRange is returned when the requested time range falls outside the input media.
+	ErrOutOfRange = errors.New("out of range")
+
+	// ErrNotFound is returned when the requested audio stream does not exist in the input media.
+	ErrNotFound = errors.New("not found")
+
+	// ErrTimeout is returned when an extraction runs for longer than the processing time limit.
+	ErrTimeout = errors.New("processing time limit exceeded")
+
+	// ErrTooLong is returned when the input media lasts longer than the media duration limit.
+	ErrTooLong = errors.New("media too long")
+)
+
+// Supported output formats.
+const (
+	FormatWAV  Format = "wav"  // PCM signed 16-bit little-endian in a WAV container
+	FormatFLAC Format = "flac" // FLAC in its native container
+	FormatMP3  Format = "mp3"  // MPEG-1 Layer III
+	FormatOpus Format = "opus" // Opus in an Ogg container
+	FormatAAC  Format = "aac"  // AAC in an MPEG-4 (m4a) container
+)
+
+// Supported channel layouts.
+const (
+	ChannelsMono       ChannelLayout = "mono"
+	ChannelsStereo     ChannelLayout = "stereo"
+	ChannelsSurround51 ChannelLayout = "5.1"
+	ChannelsSource     ChannelLayout = "source" // keep the layout of the source audio
+)
+
+var (
+	// SampleRates are the sample rates, in Hz, the extracted audio can be resampled to.
+	SampleRates = []int{8000, 11025, 12000, 16000, 22050, 24000, 32000, 44100, 48000, 88200, 96000}
+
+	// opusSampleRates are the only sample rates the Opus encoder accepts.
+	opusSampleRates = []int{8000, 12000, 16000, 24000, 48000}
+)
+
+type (
+	// Format is the codec and container of the extracted audio.
+	Format string
+
+	// ChannelLayout is the channel layout of the extracted audio.
+	ChannelLayout string
+
+	// ExtractAudioInput defines the input for the ExtractAudio method.
+	ExtractAudioInput struct {
+		SampleRate  int // In Hz, one of SampleRates
+		FilePath    string
+		InputFormat string        // The ffmpeg demuxer of the input, empty to let the command probe it
+		Format      Format        // Defaults to FormatWAV
+		Channels    ChannelLayout // Defaults to ChannelsStereo
+
+		// Start and End trim the extracted audio to the given range of the input.
+		// A zero End extracts until the end of the input.
+		Start, End time.Duration
+
+		// Stream selects the audio stream to extract.
+		// When nil, the extraction command picks one by its own heuristics.
+		Stream *StreamSelector
+
+		// AllStreams extracts every audio stream of the input into its own output instead of a single one.
+		AllStreams bool
+
+		// OnProgress, if set, is called as the extraction command reports progress.
+		OnProgress func(Progress)
+	}
+
+	// StreamSelector selects an audio stream of the input, either by position or by language.
+	StreamSelector struct {
+		Index    int    // Position among the audio streams of the input, starting at 0
+		Language string // Language tag of the stream, e.g. "eng". Takes precedence over Index when set
+	}
+
+	// ExtractAudioOutput defines the output for the ExtractAudio method.
+	// When all streams are extracted the outputs are in Tracks, and FilePath and Media are empty.
+	ExtractAudioOutput struct {
+		FilePath string
+		Media    *MediaInfo // The extracted audio as reported by the probe command
+		Tracks   []Track
+	}
+
+	// Track is the audio extracted from one stream of the input.
+	Track struct {
+		Stream   StreamInfo // The source stream in the input
+		FilePath string
+		Media    *MediaInfo // The extracted audio as reported by the probe command
+	}
+
+	ExtractCmdParams struct {
+		Context               context.Context // The command is killed once it is done
+		InputFile, OutputFile string          // Empty to read the input from Stdin and write the output to Stdout
+		InputFormat           string          // Empty to let the command probe the input
+		SampleRate            int
+		Format                Format
+		Channels              ChannelLayout
+		Start, End            time.Duration // A zero End means until the end of the input
+		AudioStream           *int          // Position among the input audio streams, nil to let the command pick
+		Stdin                 io.Reader
+		Stdout                io.Writer
+		Stderr                io.Writer
+	}
+
+	// ExtractCmd is a function that runs the extractor command.
+	ExtractCmd func(params *ExtractCmdParams) error
+
+	// Option configures an Audiostripper.
+	Option func(*Audiostripper)
+
+	// Audiostripper provides methods for extracting audio from a video file.
+	Audiostripper struct {
+		cmd         ExtractCmd
+		probeCmd    ProbeCmd
+		timeout     time.Duration // Processing time limit of an extraction, zero for no limit
+		maxDuration time.Duration // Duration limit of the input media, zero for no limit
+	}
+)
+
+// Extension returns the file extension, including the leading dot, for the format.
+func (f Format) Extension() string {
+	switch f {
+	case FormatOpus:
+		return ".ogg"
+	case FormatAAC:
+		return ".m4a"
+	default:
+		return "." + string(f)
+	}
+}
+
+// Validate reports whether the format is supported.
+func (f Format) Validate() error {
+	switch f {
+	case FormatWAV, FormatFLAC, FormatMP3, FormatOpus, FormatAAC:
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidInput, f)
+	}
+}
+
+// Validate reports whether the channel layout is supported.
+func (c ChannelLayout) Validate() error {
+	switch c {
+	case ChannelsMono, ChannelsStereo, ChannelsSurround51, ChannelsSource:
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported channel layout %q", ErrInvalidInput, c)
+	}
+}
+
+// Validate checks that the input describes a supported extraction.
+// Missing optional fields are filled with their defaults.
+func (in *ExtractAudioInput) Validate() error {
+	if in.Format == "" {
+		in.Format = FormatWAV
+	}
+
+	if in.Channels == "" {
+		in.Channels = ChannelsStereo
+	}
+
+	if err := in.Format.Validate(); err != nil {
+		return err
+	}
+
+	if err := in.Channels.Validate(); err != nil {
+		return err
+	}
+
+	if in.Format == FormatMP3 && in.Channels == ChannelsSurround51 {
+		return fmt.Errorf("%w: mp3 supports at most two channels", ErrInvalidInput)
+	}
+
+	if in.SampleRate == 0 {
+		return fmt.Errorf("%w: sample rate is required", ErrInvalidInput)
+	}
+
+	if !slices.Contains(SampleRates, in.SampleRate) {
+		return fmt.Errorf("%w: sample rate %d Hz is not supported, must be one of %v", ErrInvalidInput, in.SampleRate, SampleRates)
+	}
+
+	if in.Format == FormatOpus && !slices.Contains(opusSampleRates, in.SampleRate) {
+		return fmt.Errorf("%w: sample rate %d Hz is not supported by opus, must be one of %v", ErrInvalidInput, in.SampleRate, opusSampleRates)
+	}
+
+	if in.Format == FormatMP3 && in.SampleRate > 48000 {
+		return fmt.Errorf("%w: mp3 supports sample rates up to 48000 Hz", ErrInvalidInput)
+	}
+
+	if in.Start < 0 {
+		return fmt.Errorf("%w: start offset must not be negative", ErrInvalidInput)
+	}
+
+	if in.End != 0 && in.End <= in.Start {
+		return fmt.Errorf("%w: end %s must be after the start offset %s", ErrInvalidInput, in.End, in.Start)
+	}
+
+	if in.Stream != nil && in.AllStreams {
+		return fmt.Errorf("%w: a stream can't be selected when extracting all streams", ErrInvalidInput)
+	}
+
+	if in.Stream != nil && in.Stream.Index < 0 {
+		return fmt.Errorf("%w: stream index must not be negative", ErrInvalidInput)
+	}
+	return nil
+}
+
+// resolve returns the position among the audio streams of media of the stream matching the selector.
+func (s *StreamSelector) resolve(media *MediaInfo) (int, error) {
+	streams := media.AudioStreams()
+
+	if s.Language == "" {
+		if s.Index >= len(streams) {
+			return 0, fmt.Errorf("%w: audio stream %d requested, the input has %d", ErrNotFound, s.Index, len(streams))
+		}
+		return s.Index, nil
+	}
+
+	for i, stream := range streams {
+		if strings.EqualFold(stream.Language, s.Language) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: no audio stream with language %q", ErrNotFound, s.Language)
+}
+
+// trimmed reports whether the input asks for a range of the media rather than all of it.
+func (in *ExtractAudioInput) trimmed() bool {
+	return in.Start > 0 || in.End > 0
+}
+
+// validateRange checks that the requested range falls within media of the given duration.
+// It returns the duration of the range.
+func (in *ExtractAudioInput) validateRange(duration time.Duration) (time.Duration, error) {
+	if in.Start >= duration {
+		return 0, fmt.Errorf("%w: start offset %s is beyond the end of the %s input", ErrOutOfRange, in.Start, duration)
+	}
+
+	if in.End > duration {
+		return 0, fmt.Errorf("%w: end %s is beyond the end of the %s input", ErrOutOfRange, in.End, duration)
+	}
+
+	if in.End == 0 {
+		return duration - in.Start, nil
+	}
+	return in.End - in.Start, nil
+}
+
+// WithTimeout stops the extractions running for longer than timeout, probes included, with ErrTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(a *Audiostripper) {
+		a.timeout = timeout
+	}
+}
+
+// WithMaxDuration rejects the input media lasting longer than maxDuration with ErrTooLong.
+// Inputs are probed before being extracted to tell their duration. Those whose duration is unknown,
+// including the piped ones, are stopped once the extracted audio goes past maxDuration.
+func WithMaxDuration(maxDuration time.Duration) Option {
+	return func(a *Audiostripper) {
+		a.maxDuration = maxDuration
+	}
+}
+
+// New creates a new audtiostripper instance.
+func New(cmd ExtractCmd, probeCmd ProbeCmd, opts ...Option) *Audiostripper {
+	a := Audiostripper{
+		cmd:      cmd,
+		probeCmd: probeCmd,
+	}
+
+	for _, opt := range opts {
+		opt(&a)
+	}
+	return &a
+}
+
+// ExtractAudio extracts audio from a video file.
+func (a *Audiostripper) ExtractAudio(ctx context.Context, in *ExtractAudioInput) (*ExtractAudioOutput, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, in, stop := a.limit(ctx, in)
+	defer stop()
+
+	var (
+		audioStream *int
+		duration    time.Duration // Expected duration of the output, zero when unknown
+	)
+
+	// A trimmed extraction must fall within the input, which also tells the expected output duration,
+	// the selected streams must exist in it, and it must not exceed the media duration limit
+	if in.trimmed() || in.Stream != nil || in.AllStreams || a.maxDuration > 0 {
+		media, err := a.probe(ctx, in.FilePath, in.InputFormat)
+		if err != nil {
+			return nil, fmt.Errorf("could not probe input: %w", err)
+		}
+
+		if a.maxDuration > 0 && media.Duration > a.maxDuration {
+			return nil, fmt.Errorf("%w: the input lasts %s, the limit is %s", ErrTooLong, media.Duration, a.maxDuration)
+		}
+
+		// Media of unknown duration can't be validated, let ffmpeg extract what it can
+		if in.trimmed() && media.Duration > 0 {
+			if duration, err = in.validateRange(media.Duration); err != nil {
+				return nil, err
+			}
+		}
+
+		if in.AllStreams {
+			return a.extractTracks(ctx, in, media.AudioStreams(), duration)
+		}
+
+		if in.Stream != nil {
+			index, err := in.Stream.resolve(media)
+			if err != nil {
+				return nil, err
+			}
+			audioStream = &index
+		}
+	}
+
+	outputFile := outputFilePath(in.FilePath, in.Format)
+
+	media, err := a.extract(ctx, in, outputFile, audioStream, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExtractAudioOutput{
+		FilePath: outputFile,
+		Media:    media,
+	}, nil
+}
+
+// StreamAudio extracts audio from the video read from r, writing it to w as it is extracted.
+// Extractions needing to probe the input first, trimmed or selecting streams, can't be streamed.
+// The audio written can't be probed, it is described by the extractor command instead: its duration
+// is the one last reported, its stream the one logged, left out when the command didn't log it.
+func (a *Audiostripper) StreamAudio(ctx context.Context, in *ExtractAudioInput, r io.Reader, w io.Writer) (*MediaInfo, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	if in.trimmed() || in.Stream != nil || in.AllStreams {
+		return nil, fmt.Errorf("%w: trimmed extractions and stream selections can't be streamed", ErrInvalidInput)
+	}
+
+	ctx, in, stop := a.limit(ctx, in)
+	defer stop()
+
+	var log bytes.Buffer
+	stderr := progressWriter{w: &log, onProgress: in.OnProgress}
+
+	cmdParams := ExtractCmdParams{
+		Context:     ctx,
+		InputFormat: in.InputFormat,
+		SampleRate:  in.SampleRate,
+		Format:      in.Format,
+		Channels:    in.Channels,
+		Stdin:       r,
+		Stdout:      w,
+		Stderr:      &stderr,
+	}
+
+	if err := a.cmd(&cmdParams); err != nil {
+		stderr.Flush()
+		return nil, cmdError(ctx, "extractor", err, log.Bytes(), strings.NewReplacer())
+	}
+
+	if err := stderr.Flush(); err != nil {
+		return nil, fmt.Errorf("could not read extractor command output: %s", err)
+	}
+
+	media := MediaInfo{Duration: stderr.progress.OutTime}
+	if stderr.audio != nil {
+		media.Streams = []StreamInfo{*stderr.audio}
+	}
+	return &media, nil
+}
+
+// extractTracks extracts each of the given audio streams of the input into its own output.
+// On failure, the tracks already extracted are removed.
+func (a *Audiostripper) extractTracks(ctx context.Context, in *ExtractAudioInput, streams []StreamInfo, duration time.Duration) (*ExtractAudioOutput, error) {
+	if len(streams) == 0 {
+		return nil, fmt.Errorf("%w: the input has no audio streams", ErrNotFound)
+	}
+
+	var out ExtractAudioOutput
+
+	for i, stream := range streams {
+		audioStream := i
+		outputFile := trackFilePath(in.FilePath, audioStream, in.Format)
+
+		media, err := a.extract(ctx, in, outputFile, &audioStream, duration)
+		if err != nil {
+			for _, track := range out.Tracks {
+				os.Remove(track.FilePath)
+			}
+			return nil, fmt.Errorf("could not extract audio stream %d: %w", audioStream, err)
+		}
+
+		out.Tracks = append(out.Tracks, Track{
+			Stream:   stream,
+			FilePath: outputFile,
+			Media:    media,
+		})
+	}
+	return &out, nil
+}
+
+// extract runs the extractor command writing the audio of the input to outputFile,
+// and probes the extracted audio. A non-zero duration is the expected duration of the output.
+// On failure, including when ctx is done before the command completes, whatever the command wrote
+// to outputFile is removed.
+func (a *Audiostripper) extract(ctx context.Context, in *ExtractAudioInput, outputFile string, audioStream *int, duration time.Duration) (*MediaInfo, error) {
+	var log bytes.Buffer
+	stderr := progressWriter{w: &log, onProgress: in.OnProgress}
+	stderr.progress.Duration = duration
+
+	cmdParams := ExtractCmdParams{
+		Context:     ctx,
+		InputFile:   in.FilePath,
+		InputFormat: in.InputFormat,
+		OutputFile:  outputFile,
+		SampleRate:  in.SampleRate,
+		Format:      in.Format,
+		Channels:    in.Channels,
+		Start:       in.Start,
+		End:         in.End,
+		AudioStream: audioStream,
+		Stderr:      &stderr,
+	}
+
+	if err := a.cmd(&cmdParams); err != nil {
+		os.Remove(outputFile)
+		stderr.Flush()
+		return nil, cmdError(ctx, "extractor", err, log.Bytes(), strings.NewReplacer(in.FilePath, "input", outputFile, "output"))
+	}
+
+	if err := stderr.Flush(); err != nil {
+		os.Remove(outputFile)
+		return nil, fmt.Errorf("could not read extractor command output: %s", err)
+	}
+
+	media, err := a.probe(ctx, outputFile, "")
+	if err != nil {
+		os.Remove(outputFile)
+		return nil, fmt.Errorf("could not probe extracted audio: %w", err)
+	}
+	return media, nil
+}
+
+// limit returns the context and input to run the extraction of in with, stopped once the processing time limit
+// is reached or the extracted audio goes past the media duration limit, and the function releasing them.
+func (a *Audiostripper) limit(ctx context.Context, in *ExtractAudioInput) (context.Context, *ExtractAudioInput, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	var stopTimer context.CancelFunc = func() {}
+	if a.timeout > 0 {
+		ctx, stopTimer = context.WithTimeoutCause(ctx, a.timeout,
+			fmt.Errorf("%w: the extraction took longer than %s", ErrTimeout, a.timeout))
+	}
+
+	limited := *in
+	limited.OnProgress = func(p Progress) {
+		if a.maxDuration > 0 && p.OutTime > a.maxDuration {
+			cancel(fmt.Errorf("%w: the extracted audio goes past the %s limit", ErrTooLong, a.maxDuration))
+		}
+
+		if in.OnProgress != nil {
+			in.OnProgress(p)
+		}
+	}
+
+	return ctx, &limited, func() {
+		stopTimer()
+		cancel(nil)
+	}
+}
+
+// cmdError describes the failure of a command run for ctx, which wrote stderr. When ctx is done the command was killed,
+// and the cause of ctx is returned wrapped so that callers can tell a cancellation or a limit from a failure.
+// Otherwise it is a CmdError, with the paths of the files in stderr replaced by files.
+func cmdError(ctx context.Context, name string, err error, stderr []byte, files *strings.Replacer) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s command interrupted: %w", name, context.Cause(ctx))
+	}
+	return newCmdError(name, err, stderr, files)
+}
+
+func outputFilePath(in string, format Format) string {
+	return strings.TrimSuffix(in, filepath.Ext(in)) + format.Extension()
+}
+
+// trackFilePath returns the output path of the audio stream at the given position among the input audio streams.
+func trackFilePath(in string, audioStream int, format Format) string {
+	return strings.TrimSuffix(in, filepath.Ext(in)) + "." + strconv.Itoa(audioStream) + format.Extension()
+}
@@ -0,0 +1,491 @@
+package audiostripper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractAudio(t *testing.T) {
+	var (
+		wasCalled  bool
+		givenInput = ExtractAudioInput{
+			FilePath:   "test.mp4",
+			SampleRate: 44100,
+		}
+
+		expectedOutputFile = "test.wav"
+	)
+
+	cmdMock := func(params *ExtractCmdParams) error {
+		wasCalled = true
+
+		assert.Equal(t, givenInput.FilePath, params.InputFile)
+		assert.Equal(t, givenInput.SampleRate, params.SampleRate)
+		assert.Equal(t, FormatWAV, params.Format)
+		assert.Equal(t, ChannelsStereo, params.Channels)
+		assert.Equal(t, expectedOutputFile, params.OutputFile)
+		assert.NotNil(t, params.Stderr)
+
+		return nil
+	}
+
+	probeMock := func(params *ProbeCmdParams) error {
+		assert.Equal(t, expectedOutputFile, params.InputFile)
+
+		_, err := params.Stdout.Write([]byte(`{
+			"streams": [{"index": 0, "codec_name": "pcm_s16le", "codec_type": "audio", "sample_rate": "44100", "channels": 2}],
+			"format": {"format_name": "wav", "duration": "1.500000", "bit_rate": "1411200"}
+		}`))
+		return err
+	}
+
+	stripper := New(cmdMock, probeMock)
+
+	got, err := stripper.ExtractAudio(context.TODO(), &givenInput)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedOutputFile, got.FilePath)
+	assert.Equal(t, &MediaInfo{
+		FormatName: "wav",
+		Duration:   1500 * time.Millisecond,
+		BitRate:    1411200,
+		Streams:    []StreamInfo{{Index: 0, Type: StreamTypeAudio, Codec: "pcm_s16le", SampleRate: 44100, Channels: 2}},
+	}, got.Media)
+	assert.True(t, wasCalled)
+}
+
+func TestExtractAudioInvalidInput(t *testing.T) {
+	stripper := New(
+		func(params *ExtractCmdParams) error {
+			t.Fatal("command must not run for invalid input")
+			return nil
+		},
+		func(params *ProbeCmdParams) error {
+			t.Fatal("probe command must not run for invalid input")
+			return nil
+		},
+	)
+
+	testCases := []struct {
+		name  string
+		given ExtractAudioInput
+	}{
+		{
+			name:  "unknown format",
+			given: ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44100, Format: "wma"},
+		},
+		{
+			name:  "opus with unsupported sample rate",
+			given: ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44100, Format: FormatOpus},
+		},
+		{
+			name:  "missing sample rate",
+			given: ExtractAudioInput{FilePath: "test.mp4"},
+		},
+		{
+			name:  "unsupported sample rate",
+			given: ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44000},
+		},
+		{
+			name:  "mp3 with high sample rate",
+			given: ExtractAudioInput{FilePath: "test.mp4", SampleRate: 96000, Format: FormatMP3},
+		},
+		{
+			name:  "negative start offset",
+			given: ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44100, Start: -time.Second},
+		},
+		{
+			name:  "end before start offset",
+			given: ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44100, Start: 2 * time.Second, End: time.Second},
+		},
+		{
+			name:  "negative stream index",
+			given: ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44100, Stream: &StreamSelector{Index: -1}},
+		},
+		{
+			name:  "stream selected with all streams",
+			given: ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44100, Stream: &StreamSelector{}, AllStreams: true},
+		},
+		{
+			name:  "unknown channel layout",
+			given: ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44100, Channels: "7.1"},
+		},
+		{
+			name:  "mp3 with surround channels",
+			given: ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44100, Format: FormatMP3, Channels: ChannelsSurround51},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := stripper.ExtractAudio(context.TODO(), &tc.given)
+			require.ErrorIs(t, err, ErrInvalidInput)
+		})
+	}
+}
+
+func TestExtractAudioTrim(t *testing.T) {
+	probeMock := func(params *ProbeCmdParams) error {
+		_, err := params.Stdout.Write([]byte(`{"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "10.000000"}}`))
+		return err
+	}
+
+	t.Run("range within the input", func(t *testing.T) {
+		var progress []Progress
+
+		cmdMock := func(params *ExtractCmdParams) error {
+			assert.Equal(t, 2*time.Second, params.Start)
+			assert.Equal(t, 5*time.Second, params.End)
+
+			_, err := params.Stderr.Write([]byte("out_time_us=1500000\nprogress=continue\n"))
+			return err
+		}
+
+		_, err := New(cmdMock, probeMock).ExtractAudio(context.TODO(), &ExtractAudioInput{
+			FilePath:   "test.mp4",
+			SampleRate: 44100,
+			Start:      2 * time.Second,
+			End:        5 * time.Second,
+			OnProgress: func(p Progress) { progress = append(progress, p) },
+		})
+		require.NoError(t, err)
+
+		// Progress is relative to the duration of the range
+		require.Len(t, progress, 1)
+		assert.Equal(t, 50.0, progress[0].Percent())
+	})
+
+	testCases := []struct {
+		name       string
+		start, end time.Duration
+	}{
+		{name: "start beyond the input", start: 10 * time.Second},
+		{name: "end beyond the input", start: time.Second, end: 11 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmdMock := func(params *ExtractCmdParams) error {
+				t.Fatal("command must not run for an out of range extraction")
+				return nil
+			}
+
+			_, err := New(cmdMock, probeMock).ExtractAudio(context.TODO(), &ExtractAudioInput{
+				FilePath:   "test.mp4",
+				SampleRate: 44100,
+				Start:      tc.start,
+				End:        tc.end,
+			})
+			require.ErrorIs(t, err, ErrOutOfRange)
+		})
+	}
+}
+
+func TestExtractAudioStream(t *testing.T) {
+	probeMock := func(params *ProbeCmdParams) error {
+		_, err := params.Stdout.Write([]byte(`{"streams": [
+			{"index": 0, "codec_type": "video"},
+			{"index": 1, "codec_type": "audio", "tags": {"language": "eng"}},
+			{"index": 2, "codec_type": "audio", "tags": {"language": "por"}}
+		]}`))
+		return err
+	}
+
+	testCases := []struct {
+		name        string
+		given       StreamSelector
+		expected    int
+		expectedErr error
+	}{
+		{name: "by index", given: StreamSelector{Index: 1}, expected: 1},
+		{name: "by language", given: StreamSelector{Language: "POR"}, expected: 1},
+		{name: "language takes precedence", given: StreamSelector{Index: 1, Language: "eng"}, expected: 0},
+		{name: "index beyond the audio streams", given: StreamSelector{Index: 2}, expectedErr: ErrNotFound},
+		{name: "unknown language", given: StreamSelector{Language: "spa"}, expectedErr: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *int
+
+			cmdMock := func(params *ExtractCmdParams) error {
+				got = params.AudioStream
+				return nil
+			}
+
+			_, err := New(cmdMock, probeMock).ExtractAudio(context.TODO(), &ExtractAudioInput{
+				FilePath:   "test.mp4",
+				SampleRate: 44100,
+				Stream:     &tc.given,
+			})
+
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				assert.Nil(t, got)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, got)
+			assert.Equal(t, tc.expected, *got)
+		})
+	}
+}
+
+func TestExtractAudioAllStreams(t *testing.T) {
+	const inputProbe = `{"streams": [
+		{"index": 0, "codec_type": "video", "codec_name": "h264"},
+		{"index": 1, "codec_type": "audio", "codec_name": "aac", "tags": {"language": "eng"}},
+		{"index": 2, "codec_type": "audio", "codec_name": "opus", "tags": {"language": "por"}}
+	]}`
+
+	probeMock := func(params *ProbeCmdParams) error {
+		output := `{"streams": [{"index": 0, "codec_type": "audio", "codec_name": "pcm_s16le"}]}`
+		if params.InputFile == "test.mp4" {
+			output = inputProbe
+		}
+
+		_, err := params.Stdout.Write([]byte(output))
+		return err
+	}
+
+	var extracted []int
+
+	cmdMock := func(params *ExtractCmdParams) error {
+		require.NotNil(t, params.AudioStream)
+		extracted = append(extracted, *params.AudioStream)
+		return nil
+	}
+
+	got, err := New(cmdMock, probeMock).ExtractAudio(context.TODO(), &ExtractAudioInput{
+		FilePath:   "test.mp4",
+		SampleRate: 44100,
+		AllStreams: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{0, 1}, extracted)
+	assert.Empty(t, got.FilePath)
+
+	require.Len(t, got.Tracks, 2)
+
+	assert.Equal(t, StreamInfo{Index: 1, Type: StreamTypeAudio, Codec: "aac", Language: "eng"}, got.Tracks[0].Stream)
+	assert.Equal(t, "test.0.wav", got.Tracks[0].FilePath)
+	assert.Equal(t, "pcm_s16le", got.Tracks[0].Media.Streams[0].Codec)
+
+	assert.Equal(t, StreamInfo{Index: 2, Type: StreamTypeAudio, Codec: "opus", Language: "por"}, got.Tracks[1].Stream)
+	assert.Equal(t, "test.1.wav", got.Tracks[1].FilePath)
+
+	t.Run("input without audio", func(t *testing.T) {
+		probeMock := func(params *ProbeCmdParams) error {
+			_, err := params.Stdout.Write([]byte(`{"streams": [{"index": 0, "codec_type": "video"}]}`))
+			return err
+		}
+
+		_, err := New(cmdMock, probeMock).ExtractAudio(context.TODO(), &ExtractAudioInput{
+			FilePath:   "test.mp4",
+			SampleRate: 44100,
+			AllStreams: true,
+		})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestExtractAudioCleanup(t *testing.T) {
+	dir := t.TempDir()
+
+	// The command fails after writing part of the output
+	cmdMock := func(params *ExtractCmdParams) error {
+		assert.NoError(t, os.WriteFile(params.OutputFile, []byte("audio"), 0o600))
+		if *params.AudioStream == 1 {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}
+
+	probeMock := func(params *ProbeCmdParams) error {
+		_, err := params.Stdout.Write([]byte(`{"streams": [{"index": 0, "codec_type": "audio"}, {"index": 1, "codec_type": "audio"}]}`))
+		return err
+	}
+
+	_, err := New(cmdMock, probeMock).ExtractAudio(context.TODO(), &ExtractAudioInput{
+		FilePath:   filepath.Join(dir, "test.mp4"),
+		SampleRate: 44100,
+		AllStreams: true,
+	})
+	require.Error(t, err)
+
+	// Neither the failed track nor the ones extracted before are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestExtractAudioCanceled(t *testing.T) {
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The command is killed by the cancellation after writing part of the output
+	cmdMock := func(params *ExtractCmdParams) error {
+		assert.NoError(t, os.WriteFile(params.OutputFile, []byte("audio"), 0o600))
+		cancel()
+		<-params.Context.Done()
+		return errors.New("signal: killed")
+	}
+
+	probeMock := func(params *ProbeCmdParams) error {
+		t.Error("the output of a canceled extraction must not be probed")
+		return nil
+	}
+
+	_, err := New(cmdMock, probeMock).ExtractAudio(ctx, &ExtractAudioInput{
+		FilePath:   filepath.Join(dir, "test.mp4"),
+		SampleRate: 44100,
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestExtractAudioLimits(t *testing.T) {
+	// The input lasts 2 hours
+	probeMock := func(params *ProbeCmdParams) error {
+		_, err := params.Stdout.Write([]byte(`{"streams": [{"index": 0, "codec_type": "audio"}], "format": {"duration": "7200.000000"}}`))
+		return err
+	}
+
+	unknownDurationProbeMock := func(params *ProbeCmdParams) error {
+		_, err := params.Stdout.Write([]byte(`{"streams": [{"index": 0, "codec_type": "audio"}], "format": {"duration": "N/A"}}`))
+		return err
+	}
+
+	// The command runs until it is killed
+	blockingCmdMock := func(params *ExtractCmdParams) error {
+		<-params.Context.Done()
+		return errors.New("signal: killed")
+	}
+
+	// The command reports 90 minutes of extracted audio, then runs until it is killed
+	progressCmdMock := func(params *ExtractCmdParams) error {
+		if _, err := params.Stderr.Write([]byte("out_time_us=5400000000\nprogress=continue\n")); err != nil {
+			return err
+		}
+		<-params.Context.Done()
+		return errors.New("signal: killed")
+	}
+
+	t.Run("input too long", func(t *testing.T) {
+		cmdMock := func(params *ExtractCmdParams) error {
+			t.Error("the extractor command must not run for an input too long")
+			return nil
+		}
+
+		_, err := New(cmdMock, probeMock, WithMaxDuration(time.Hour)).ExtractAudio(context.TODO(), &ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44100})
+		require.ErrorIs(t, err, ErrTooLong)
+	})
+
+	t.Run("input of unknown duration too long", func(t *testing.T) {
+		var reported []Progress
+
+		_, err := New(progressCmdMock, unknownDurationProbeMock, WithMaxDuration(time.Hour)).ExtractAudio(context.TODO(), &ExtractAudioInput{
+			FilePath:   "test.mp4",
+			SampleRate: 44100,
+			OnProgress: func(p Progress) { reported = append(reported, p) },
+		})
+		require.ErrorIs(t, err, ErrTooLong)
+		assert.Len(t, reported, 1)
+	})
+
+	t.Run("streamed input too long", func(t *testing.T) {
+		_, err := New(progressCmdMock, probeMock, WithMaxDuration(time.Hour)).StreamAudio(context.TODO(), &ExtractAudioInput{SampleRate: 44100}, strings.NewReader("videoData"), io.Discard)
+		require.ErrorIs(t, err, ErrTooLong)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := New(blockingCmdMock, probeMock, WithTimeout(10*time.Millisecond)).ExtractAudio(context.TODO(), &ExtractAudioInput{FilePath: "test.mp4", SampleRate: 44100})
+		require.ErrorIs(t, err, ErrTimeout)
+	})
+}
+
+// streamedOutputLog is the stderr of ffmpeg extracting Opus audio from a piped video.
+const streamedOutputLog = `Input #0, matroska,webm, from 'pipe:0':
+  Duration: N/A, start: 0.000000, bitrate: N/A
+  Stream #0:0: Video: vp9 (Profile 0), yuv420p(tv), 1920x1080, 25 fps
+  Stream #0:1(eng): Audio: aac (LC), 44100 Hz, stereo, fltp (default)
+Stream mapping:
+  Stream #0:1 -> #0:0 (aac (native) -> opus (libopus))
+Output #0, ogg, to 'pipe:1':
+  Metadata:
+    encoder         : Lavf60.3.100
+  Stream #0:0(eng): Audio: opus, 48000 Hz, 5.1(side), s16, 64 kb/s (default)
+out_time_us=2500000
+progress=end
+`
+
+func TestStreamAudio(t *testing.T) {
+	cmdMock := func(params *ExtractCmdParams) error {
+		assert.Empty(t, params.InputFile)
+		assert.Empty(t, params.OutputFile)
+		assert.Equal(t, FormatOpus, params.Format)
+
+		fmt.Fprint(params.Stderr, streamedOutputLog)
+
+		// Echo the input as the extracted audio
+		_, err := io.Copy(params.Stdout, params.Stdin)
+		return err
+	}
+
+	probeMock := func(params *ProbeCmdParams) error {
+		t.Error("the streamed input must not be probed")
+		return nil
+	}
+
+	stripper := New(cmdMock, probeMock)
+
+	var out bytes.Buffer
+	media, err := stripper.StreamAudio(context.TODO(), &ExtractAudioInput{SampleRate: 48000, Format: FormatOpus}, strings.NewReader("videoData"), &out)
+	require.NoError(t, err)
+	assert.Equal(t, "videoData", out.String())
+
+	// The audio is described by the extractor command log rather than probed
+	assert.Equal(t, &MediaInfo{
+		Duration: 2500 * time.Millisecond,
+		Streams:  []StreamInfo{{Type: StreamTypeAudio, Codec: "opus", SampleRate: 48000, Channels: 6}},
+	}, media)
+
+	t.Run("input needing a probe", func(t *testing.T) {
+		_, err := stripper.StreamAudio(context.TODO(), &ExtractAudioInput{SampleRate: 48000, Start: time.Second}, strings.NewReader("videoData"), io.Discard)
+		require.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
+
+func TestOutputFilePath(t *testing.T) {
+	testCases := []struct {
+		given  string
+		format Format
+		want   string
+	}{
+		{given: "/tmp/test.mp4", format: FormatWAV, want: "/tmp/test.wav"},
+		{given: "/tmp/input-123", format: FormatFLAC, want: "/tmp/input-123.flac"},
+		{given: "/tmp/test.mkv", format: FormatOpus, want: "/tmp/test.ogg"},
+		{given: "/tmp/test.mov", format: FormatAAC, want: "/tmp/test.m4a"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, outputFilePath(tc.given, tc.format))
+	}
+}
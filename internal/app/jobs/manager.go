@@ -0,0 +1,580 @@
+// Package jobs runs audio extractions in the background and keeps track of their state,
+// so the upload, the extraction and the download of the result don't have to share a connection.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+)
+
+var (
+	// ErrNotFound is returned when no job has the requested ID.
+	ErrNotFound = errors.New("job not found")
+
+	// ErrFinished is returned when canceling a job that already finished.
+	ErrFinished = errors.New("job already finished")
+
+	// ErrInterrupted is the error of jobs that were interrupted by a restart and could not be resumed.
+	ErrInterrupted = errors.New("job interrupted")
+
+	// ErrQueueFull is returned when submitting a job while too many jobs are queued already.
+	ErrQueueFull = errors.New("job queue full")
+)
+
+// States of a job.
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+	StateCanceled  State = "canceled"
+)
+
+type (
+	// State is the state of a job.
+	State string
+
+	// Job is a snapshot of an extraction job.
+	Job struct {
+		ID        string
+		Owner     string // Identity of the client that submitted the job, empty for anonymous clients
+		State     State
+		Input     audiostripper.ExtractAudioInput
+		Metadata  map[string]string // Client metadata of the request, not interpreted
+		Progress  audiostripper.Progress
+		Output    *audiostripper.ExtractAudioOutput // Set once the job succeeded, until its result expires
+		Err       error                             // Set once the job failed
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		ExpiresAt time.Time // When the result of a succeeded job is removed, zero to keep it
+	}
+
+	// Executor runs the extraction of a job.
+	Executor interface {
+		ExtractAudio(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error)
+	}
+
+	// Store persists jobs so they survive restarts.
+	Store interface {
+		Save(job *Job) error
+		Load() ([]*Job, error)
+		Delete(id string) error
+	}
+
+	// Workers bounds the number of jobs running at once.
+	Workers interface {
+		// Wait blocks until a worker is free or ctx is done, and returns the function releasing the worker.
+		Wait(ctx context.Context) (release func(), err error)
+	}
+
+	// Option configures a Manager.
+	Option func(*Manager)
+
+	// Manager runs extraction jobs on an executor and keeps track of them.
+	Manager struct {
+		logger    *slog.Logger
+		executor  Executor
+		store     Store         // nil to keep the jobs in memory only
+		dir       string        // Directory the job files are moved to, empty to leave them where they are
+		resultTTL time.Duration // How long results are kept once the job succeeded, zero to keep them
+		retention time.Duration // How long finished jobs are kept once they stopped changing, zero to keep them
+		workers   Workers       // nil to run every job right away
+		maxQueued int           // Number of queued jobs beyond which Submit fails, zero for no limit
+
+		mu   sync.Mutex
+		jobs map[string]*entry
+	}
+
+	entry struct {
+		job     Job
+		cancel  context.CancelFunc
+		changed chan struct{} // Closed and replaced every time the job changes
+	}
+)
+
+// Finished reports whether the job reached a final state.
+func (s State) Finished() bool {
+	return s == StateSucceeded || s == StateFailed || s == StateCanceled
+}
+
+// WithStore persists the jobs in store. See Manager.Recover to load them back on startup.
+func WithStore(store Store) Option {
+	return func(m *Manager) {
+		m.store = store
+	}
+}
+
+// WithDir moves the input of every submitted job into dir, where its output is extracted as well,
+// so the files of the jobs outlive the temp directory.
+func WithDir(dir string) Option {
+	return func(m *Manager) {
+		m.dir = dir
+	}
+}
+
+// WithResultTTL keeps the result of a job for ttl once it succeeded, see Manager.RemoveExpired.
+func WithResultTTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.resultTTL = ttl
+	}
+}
+
+// WithRetention forgets finished jobs once they didn't change for retention, along with their result
+// if still around, see Manager.RemoveExpired.
+func WithRetention(retention time.Duration) Option {
+	return func(m *Manager) {
+		m.retention = retention
+	}
+}
+
+// WithWorkers runs the jobs on workers, the jobs staying queued until a worker is free.
+func WithWorkers(workers Workers) Option {
+	return func(m *Manager) {
+		m.workers = workers
+	}
+}
+
+// WithMaxQueued fails the submission of jobs with ErrQueueFull while n jobs are queued already,
+// waiting for a worker. Jobs resumed by Recover are not bounded.
+func WithMaxQueued(n int) Option {
+	return func(m *Manager) {
+		m.maxQueued = n
+	}
+}
+
+// NewManager creates a job manager running the extractions on executor.
+func NewManager(logger *slog.Logger, executor Executor, opts ...Option) *Manager {
+	m := Manager{
+		logger:   logger,
+		executor: executor,
+		jobs:     make(map[string]*entry),
+	}
+
+	for _, opt := range opts {
+		opt(&m)
+	}
+	return &m
+}
+
+// Recover loads the jobs of the store. Jobs that were queued or running when the service stopped
+// are run again if their input is still around, and failed with ErrInterrupted otherwise.
+func (m *Manager) Recover() error {
+	if m.store == nil {
+		return nil
+	}
+
+	jobs, err := m.store.Load()
+	if err != nil {
+		return fmt.Errorf("could not load jobs: %s", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, job := range jobs {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		e := entry{job: *job, cancel: cancel, changed: make(chan struct{})}
+		m.jobs[job.ID] = &e
+
+		if job.State.Finished() {
+			continue
+		}
+
+		if _, err := os.Stat(job.Input.FilePath); err != nil {
+			e.job.Err = fmt.Errorf("%w: input is no longer available", ErrInterrupted)
+			m.setState(&e, StateFailed)
+			e.notify()
+			continue
+		}
+
+		m.logger.Info("Resuming job", slog.String("job_id", job.ID), slog.String("state", string(job.State)))
+
+		m.setState(&e, StateQueued)
+		e.notify()
+
+		go m.run(ctx, job.ID, e.job.Input)
+	}
+	return nil
+}
+
+// Submit queues the extraction of in on behalf of owner and returns the new job without waiting for it.
+// The job owns the input file from then on and removes it once the job finishes.
+// On failure, such as ErrQueueFull when the queue is full, the input is left to the caller at in.FilePath.
+func (m *Manager) Submit(owner string, in *audiostripper.ExtractAudioInput, metadata map[string]string) (*Job, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate job id: %s", err)
+	}
+
+	// Don't move an input bound to be rejected, it is checked again once the job is about to be queued
+	m.mu.Lock()
+	err = m.queueFull()
+	m.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if m.dir != "" {
+		path := filepath.Join(m.dir, id+filepath.Ext(in.FilePath))
+		if err := moveFile(in.FilePath, path); err != nil {
+			return nil, fmt.Errorf("could not move job input: %s", err)
+		}
+		in.FilePath = path
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	now := time.Now()
+	e := entry{
+		job: Job{
+			ID:        id,
+			Owner:     owner,
+			State:     StateQueued,
+			Input:     *in,
+			Metadata:  metadata,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		cancel:  cancel,
+		changed: make(chan struct{}),
+	}
+
+	// The progress of the job is reported through the job itself
+	e.job.Input.OnProgress = nil
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.queueFull(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if err := m.save(&e.job); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	m.jobs[id] = &e
+	job := e.job
+
+	go m.run(ctx, id, job.Input)
+
+	return &job, nil
+}
+
+// queueFull fails with ErrQueueFull when the queue is full. It must be called with the manager lock held.
+func (m *Manager) queueFull() error {
+	if m.maxQueued == 0 {
+		return nil
+	}
+
+	var queued int
+	for _, e := range m.jobs {
+		if e.job.State == StateQueued {
+			queued++
+		}
+	}
+
+	if queued >= m.maxQueued {
+		return fmt.Errorf("%w: %d jobs waiting for a worker", ErrQueueFull, queued)
+	}
+	return nil
+}
+
+// Get returns the job with the given ID.
+func (m *Manager) Get(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	job := e.job
+	return &job, nil
+}
+
+// List returns every job, oldest first.
+func (m *Manager) List() []*Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]*Job, 0, len(m.jobs))
+	for _, e := range m.jobs {
+		job := e.job
+		jobs = append(jobs, &job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].ID < jobs[j].ID
+		}
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// Cancel cancels the job with the given ID. The result of a canceled job is discarded.
+func (m *Manager) Cancel(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	if e.job.State.Finished() {
+		return nil, fmt.Errorf("%w: job %s is %s", ErrFinished, id, e.job.State)
+	}
+
+	e.cancel()
+	m.setState(e, StateCanceled)
+	e.notify()
+
+	job := e.job
+	return &job, nil
+}
+
+// Watch calls fn with the job with the given ID, and again every time it changes, until it finishes.
+// It returns early when ctx is done or fn fails.
+func (m *Manager) Watch(ctx context.Context, id string, fn func(*Job) error) error {
+	for {
+		m.mu.Lock()
+		e, ok := m.jobs[id]
+		if !ok {
+			m.mu.Unlock()
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		job, changed := e.job, e.changed
+		m.mu.Unlock()
+
+		if err := fn(&job); err != nil {
+			return err
+		}
+
+		if job.State.Finished() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// run executes the job and records its outcome.
+func (m *Manager) run(ctx context.Context, id string, in audiostripper.ExtractAudioInput) {
+	// Fails only when the job is canceled while waiting, which is handled below
+	if m.workers != nil {
+		if release, err := m.workers.Wait(ctx); err == nil {
+			defer release()
+		}
+	}
+
+	m.update(id, func(e *entry) {
+		// A job canceled before it started is not run
+		if e.job.State == StateQueued {
+			m.setState(e, StateRunning)
+		}
+	})
+
+	in.OnProgress = func(p audiostripper.Progress) {
+		m.update(id, func(e *entry) { e.job.Progress = p })
+	}
+
+	var (
+		output *audiostripper.ExtractAudioOutput
+		err    = context.Canceled
+	)
+
+	if ctx.Err() == nil {
+		output, err = m.executor.ExtractAudio(ctx, &in)
+	}
+
+	// The input is not needed anymore, whatever the outcome
+	m.remove(in.FilePath)
+
+	m.update(id, func(e *entry) {
+		switch {
+		case e.job.State == StateCanceled:
+			if output != nil {
+				m.removeOutput(output)
+			}
+		case err != nil:
+			e.job.Err = err
+			m.setState(e, StateFailed)
+		default:
+			e.job.Output = output
+			if m.resultTTL > 0 {
+				e.job.ExpiresAt = time.Now().Add(m.resultTTL)
+			}
+			m.setState(e, StateSucceeded)
+		}
+	})
+}
+
+// RemoveExpired removes the results of the jobs that expired, and forgets the finished jobs
+// past their retention period.
+func (m *Manager) RemoveExpired() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	for id, e := range m.jobs {
+		if m.retention > 0 && e.job.State.Finished() && now.Sub(e.job.UpdatedAt) >= m.retention {
+			m.forget(id, e)
+			continue
+		}
+
+		if e.job.Output == nil || e.job.ExpiresAt.IsZero() || now.Before(e.job.ExpiresAt) {
+			continue
+		}
+
+		m.removeOutput(e.job.Output)
+		e.job.Output = nil
+
+		if err := m.save(&e.job); err != nil {
+			m.logger.Error("Failed to save job", slog.String("job_id", e.job.ID), slog.String("error", err.Error()))
+		}
+		e.notify()
+	}
+}
+
+// forget removes a finished job, its result and its record. It must be called with the manager lock held.
+func (m *Manager) forget(id string, e *entry) {
+	if e.job.Output != nil {
+		m.removeOutput(e.job.Output)
+	}
+
+	// A record left behind is forgotten on the next sweep after a restart
+	if m.store != nil {
+		if err := m.store.Delete(id); err != nil {
+			m.logger.Error("Failed to delete job", slog.String("job_id", id), slog.String("error", err.Error()))
+			return
+		}
+	}
+
+	delete(m.jobs, id)
+	m.logger.Info("Job forgotten", slog.String("job_id", id))
+}
+
+// update applies fn to the job with the given ID under the manager lock and notifies its watchers.
+func (m *Manager) update(id string, fn func(e *entry)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.jobs[id]; ok {
+		fn(e)
+		e.notify()
+	}
+}
+
+// setState moves the job to state and persists it. It must be called with the manager lock held.
+func (m *Manager) setState(e *entry, state State) {
+	e.job.State = state
+	e.job.UpdatedAt = time.Now()
+
+	if state.Finished() {
+		m.logger.Info("Job finished", slog.String("job_id", e.job.ID), slog.String("state", string(state)))
+	}
+
+	// The job carries on in memory, it is only at risk of being lost on restart
+	if err := m.save(&e.job); err != nil {
+		m.logger.Error("Failed to save job", slog.String("job_id", e.job.ID), slog.String("error", err.Error()))
+	}
+}
+
+// save persists the job, if the manager has a store.
+func (m *Manager) save(job *Job) error {
+	if m.store == nil {
+		return nil
+	}
+
+	if err := m.store.Save(job); err != nil {
+		return fmt.Errorf("could not save job %s: %s", job.ID, err)
+	}
+	return nil
+}
+
+// notify records that the job changed and wakes up its watchers.
+// It must be called with the manager lock held.
+func (e *entry) notify() {
+	e.job.UpdatedAt = time.Now()
+	close(e.changed)
+	e.changed = make(chan struct{})
+}
+
+// removeOutput removes the files extracted for output.
+func (m *Manager) removeOutput(output *audiostripper.ExtractAudioOutput) {
+	if output.FilePath != "" {
+		m.remove(output.FilePath)
+	}
+	for _, track := range output.Tracks {
+		m.remove(track.FilePath)
+	}
+}
+
+func (m *Manager) remove(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		m.logger.Error("Failed to remove job file", slog.String("file", path), slog.String("error", err.Error()))
+	}
+}
+
+// moveFile moves the file at src to dst, copying it when they are on different file systems.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
+
+// newID returns a random job ID.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
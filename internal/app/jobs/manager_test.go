@@ -0,0 +1,420 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/workers"
+)
+
+type executorFunc func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error)
+
+func (f executorFunc) ExtractAudio(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+	return f(ctx, in)
+}
+
+func TestManagerSubmit(t *testing.T) {
+	input := inputFileHelper(t)
+
+	executor := executorFunc(func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+		assert.Equal(t, input, in.FilePath)
+		assert.Equal(t, 44100, in.SampleRate)
+
+		in.OnProgress(audiostripper.Progress{OutTime: time.Second, Duration: 2 * time.Second})
+		return &audiostripper.ExtractAudioOutput{FilePath: "out.wav"}, nil
+	})
+
+	manager := NewManager(noopLogger(), executor)
+
+	job, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: input, SampleRate: 44100}, map[string]string{"request_id": "1"})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, job.ID)
+	assert.Equal(t, StateQueued, job.State)
+	assert.Equal(t, map[string]string{"request_id": "1"}, job.Metadata)
+
+	var states []State
+	require.NoError(t, manager.Watch(context.TODO(), job.ID, func(j *Job) error {
+		if len(states) == 0 || states[len(states)-1] != j.State {
+			states = append(states, j.State)
+		}
+		return nil
+	}))
+
+	// Updates may be coalesced, but the job always ends up succeeded
+	assert.Equal(t, StateSucceeded, states[len(states)-1])
+
+	got, err := manager.Get(job.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, StateSucceeded, got.State)
+	assert.Equal(t, "out.wav", got.Output.FilePath)
+	assert.Equal(t, time.Second, got.Progress.OutTime)
+	assert.NoError(t, got.Err)
+
+	// The input is removed once the job finished
+	assert.NoFileExists(t, input)
+}
+
+func TestManagerFailedJob(t *testing.T) {
+	executor := executorFunc(func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+		return nil, errors.New("ffmpeg failed")
+	})
+
+	manager := NewManager(noopLogger(), executor)
+
+	job, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: inputFileHelper(t)}, nil)
+	require.NoError(t, err)
+
+	got := waitHelper(t, manager, job.ID)
+
+	assert.Equal(t, StateFailed, got.State)
+	assert.EqualError(t, got.Err, "ffmpeg failed")
+	assert.Nil(t, got.Output)
+}
+
+func TestManagerCancel(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		output  = filepath.Join(t.TempDir(), "out.wav")
+	)
+
+	executor := executorFunc(func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+		close(started)
+		<-release
+
+		assert.Error(t, ctx.Err())
+
+		assert.NoError(t, os.WriteFile(output, []byte("audio"), 0o600))
+		return &audiostripper.ExtractAudioOutput{FilePath: output}, nil
+	})
+
+	manager := NewManager(noopLogger(), executor)
+
+	job, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: inputFileHelper(t)}, nil)
+	require.NoError(t, err)
+
+	<-started
+
+	canceled, err := manager.Cancel(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateCanceled, canceled.State)
+
+	close(release)
+
+	// The result of a canceled job is discarded
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(output)
+		return errors.Is(err, os.ErrNotExist)
+	}, time.Second, 10*time.Millisecond)
+
+	got, err := manager.Get(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateCanceled, got.State)
+	assert.Nil(t, got.Output)
+
+	_, err = manager.Cancel(job.ID)
+	require.ErrorIs(t, err, ErrFinished)
+}
+
+func TestManagerNotFound(t *testing.T) {
+	manager := NewManager(noopLogger(), nil)
+
+	_, err := manager.Get("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = manager.Cancel("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	err = manager.Watch(context.TODO(), "missing", func(*Job) error { return nil })
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManagerList(t *testing.T) {
+	executor := executorFunc(func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+		return &audiostripper.ExtractAudioOutput{}, nil
+	})
+
+	manager := NewManager(noopLogger(), executor)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		job, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: inputFileHelper(t)}, nil)
+		require.NoError(t, err)
+		ids = append(ids, job.ID)
+	}
+
+	var got []string
+	for _, job := range manager.List() {
+		got = append(got, job.ID)
+	}
+
+	assert.ElementsMatch(t, ids, got)
+}
+
+func TestManagerRecover(t *testing.T) {
+	var (
+		dir     = t.TempDir()
+		resumed = filepath.Join(dir, "resumed.mp4")
+	)
+
+	require.NoError(t, os.WriteFile(resumed, []byte("videoData"), 0o600))
+
+	store := memoryStore{mu: &sync.Mutex{}, jobs: map[string]*Job{
+		"done":    {ID: "done", State: StateSucceeded, Output: &audiostripper.ExtractAudioOutput{FilePath: "done.wav"}},
+		"resumed": {ID: "resumed", State: StateRunning, Input: audiostripper.ExtractAudioInput{FilePath: resumed}},
+		"lost":    {ID: "lost", State: StateQueued, Input: audiostripper.ExtractAudioInput{FilePath: filepath.Join(dir, "lost.mp4")}},
+	}}
+
+	executor := executorFunc(func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+		assert.Equal(t, resumed, in.FilePath)
+		return &audiostripper.ExtractAudioOutput{FilePath: "resumed.wav"}, nil
+	})
+
+	manager := NewManager(noopLogger(), executor, WithStore(store))
+	require.NoError(t, manager.Recover())
+
+	assert.Len(t, manager.List(), 3)
+
+	got := waitHelper(t, manager, "resumed")
+	assert.Equal(t, StateSucceeded, got.State)
+	assert.Equal(t, "resumed.wav", got.Output.FilePath)
+
+	got = waitHelper(t, manager, "lost")
+	assert.Equal(t, StateFailed, got.State)
+	assert.ErrorIs(t, got.Err, ErrInterrupted)
+
+	got = waitHelper(t, manager, "done")
+	assert.Equal(t, "done.wav", got.Output.FilePath)
+
+	// The outcome of the recovered jobs is stored
+	assert.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return store.jobs["resumed"].State == StateSucceeded && store.jobs["lost"].State == StateFailed
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestManagerWithDir(t *testing.T) {
+	dir := t.TempDir()
+
+	executor := executorFunc(func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+		assert.Equal(t, dir, filepath.Dir(in.FilePath))
+		assert.FileExists(t, in.FilePath)
+		return &audiostripper.ExtractAudioOutput{}, nil
+	})
+
+	manager := NewManager(noopLogger(), executor, WithDir(dir))
+
+	input := inputFileHelper(t)
+
+	job, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: input}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, job.ID+".mp4"), job.Input.FilePath)
+	assert.NoFileExists(t, input)
+
+	assert.Equal(t, StateSucceeded, waitHelper(t, manager, job.ID).State)
+}
+
+func TestManagerRemoveExpired(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.wav")
+
+	executor := executorFunc(func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+		assert.NoError(t, os.WriteFile(output, []byte("audio"), 0o600))
+		return &audiostripper.ExtractAudioOutput{FilePath: output}, nil
+	})
+
+	manager := NewManager(noopLogger(), executor, WithResultTTL(time.Millisecond))
+
+	job, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: inputFileHelper(t)}, nil)
+	require.NoError(t, err)
+
+	got := waitHelper(t, manager, job.ID)
+	require.Equal(t, StateSucceeded, got.State)
+	assert.False(t, got.ExpiresAt.IsZero())
+
+	time.Sleep(5 * time.Millisecond)
+	manager.RemoveExpired()
+
+	// The job is kept, without its result
+	got, err = manager.Get(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateSucceeded, got.State)
+	assert.Nil(t, got.Output)
+	assert.NoFileExists(t, output)
+}
+
+func TestManagerRetention(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.wav")
+
+	executor := executorFunc(func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+		assert.NoError(t, os.WriteFile(output, []byte("audio"), 0o600))
+		return &audiostripper.ExtractAudioOutput{FilePath: output}, nil
+	})
+
+	store := memoryStore{mu: &sync.Mutex{}, jobs: map[string]*Job{}}
+	manager := NewManager(noopLogger(), executor, WithStore(store), WithRetention(time.Millisecond))
+
+	job, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: inputFileHelper(t)}, nil)
+	require.NoError(t, err)
+	require.Equal(t, StateSucceeded, waitHelper(t, manager, job.ID).State)
+
+	time.Sleep(5 * time.Millisecond)
+	manager.RemoveExpired()
+
+	// The job is forgotten, along with its result and its record
+	_, err = manager.Get(job.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Empty(t, manager.List())
+	assert.NoFileExists(t, output)
+
+	stored, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestManagerWithWorkers(t *testing.T) {
+	release := make(chan struct{})
+
+	executor := executorFunc(func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+		<-release
+		return &audiostripper.ExtractAudioOutput{}, nil
+	})
+
+	manager := NewManager(noopLogger(), executor, WithWorkers(workers.NewPool(1, 0)))
+
+	first, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: inputFileHelper(t)}, nil)
+	require.NoError(t, err)
+
+	second, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: inputFileHelper(t)}, nil)
+	require.NoError(t, err)
+
+	// One job runs, the other waits for the worker
+	assert.Eventually(t, func() bool {
+		states := map[State]int{}
+		for _, job := range manager.List() {
+			states[job.State]++
+		}
+		return states[StateRunning] == 1 && states[StateQueued] == 1
+	}, time.Second, time.Millisecond)
+
+	close(release)
+
+	assert.Equal(t, StateSucceeded, waitHelper(t, manager, first.ID).State)
+	assert.Equal(t, StateSucceeded, waitHelper(t, manager, second.ID).State)
+}
+
+func TestManagerMaxQueued(t *testing.T) {
+	release := make(chan struct{})
+
+	executor := executorFunc(func(ctx context.Context, in *audiostripper.ExtractAudioInput) (*audiostripper.ExtractAudioOutput, error) {
+		<-release
+		return &audiostripper.ExtractAudioOutput{}, nil
+	})
+
+	dir := t.TempDir()
+
+	manager := NewManager(noopLogger(), executor, WithWorkers(workers.NewPool(1, 0)), WithMaxQueued(1), WithDir(dir))
+
+	running, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: inputFileHelper(t)}, nil)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		got, err := manager.Get(running.ID)
+		return err == nil && got.State == StateRunning
+	}, time.Second, time.Millisecond)
+
+	queued, err := manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: inputFileHelper(t)}, nil)
+	require.NoError(t, err)
+
+	// The queue is full, the input is left to the caller
+	input := inputFileHelper(t)
+	_, err = manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: input}, nil)
+	require.ErrorIs(t, err, ErrQueueFull)
+	assert.FileExists(t, input)
+	assert.Len(t, manager.List(), 2)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	close(release)
+
+	assert.Equal(t, StateSucceeded, waitHelper(t, manager, running.ID).State)
+	assert.Equal(t, StateSucceeded, waitHelper(t, manager, queued.ID).State)
+
+	// Jobs are accepted again once the queue drained
+	_, err = manager.Submit("", &audiostripper.ExtractAudioInput{FilePath: input}, nil)
+	require.NoError(t, err)
+}
+
+func waitHelper(t *testing.T, manager *Manager, id string) *Job {
+	t.Helper()
+
+	var last *Job
+	require.NoError(t, manager.Watch(context.TODO(), id, func(j *Job) error {
+		last = j
+		return nil
+	}))
+	return last
+}
+
+func inputFileHelper(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "input.mp4")
+	require.NoError(t, os.WriteFile(path, []byte("videoData"), 0o600))
+	return path
+}
+
+// memoryStore is a Store keeping the jobs in a map.
+type memoryStore struct {
+	mu   *sync.Mutex
+	jobs map[string]*Job
+}
+
+func (s memoryStore) Save(job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := *job
+	s.jobs[job.ID] = &saved
+	return nil
+}
+
+func (s memoryStore) Load() ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []*Job
+	for _, job := range s.jobs {
+		saved := *job
+		jobs = append(jobs, &saved)
+	}
+	return jobs, nil
+}
+
+func (s memoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	return nil
+}
+
+func noopLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
@@ -0,0 +1,256 @@
+// Package uploads implements resumable uploads: a video is written to an upload session in chunks,
+// possibly over several connections, and handed over once complete.
+package uploads
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when no session has the requested ID, or it expired.
+	ErrNotFound = errors.New("upload session not found")
+
+	// ErrOffsetMismatch is returned when a chunk doesn't start at the committed offset of the session.
+	ErrOffsetMismatch = errors.New("offset mismatch")
+
+	// ErrTooLarge is returned when a chunk would grow the upload beyond its expected size.
+	ErrTooLarge = errors.New("upload too large")
+
+	// ErrIncomplete is returned when completing a session that didn't receive its expected size yet.
+	ErrIncomplete = errors.New("upload incomplete")
+
+	// ErrLimitExceeded is returned when an upload would grow beyond the maximum size of its session.
+	// The session is discarded.
+	ErrLimitExceeded = errors.New("upload limit exceeded")
+)
+
+type (
+	// Session is a snapshot of an upload session.
+	Session struct {
+		ID           string
+		Owner        string // Identity of the client that created the session, empty for anonymous clients
+		Offset       uint64 // Number of bytes committed so far, where the next chunk starts
+		ExpectedSize uint64 // Zero when unknown
+		MaxSize      uint64 // Zero when unlimited
+		ExpiresAt    time.Time
+	}
+
+	// Manager keeps track of the upload sessions and their partial files.
+	Manager struct {
+		dir string
+		ttl time.Duration
+
+		mu       sync.Mutex
+		sessions map[string]*session
+	}
+
+	session struct {
+		mu      sync.Mutex // Serializes the writes to the session
+		session Session
+		path    string
+		closed  bool // The session completed or expired, its file is not ours anymore
+	}
+)
+
+// NewManager creates an upload session manager writing the partial files to dir, the default
+// temp directory when empty. Sessions expire once they didn't receive any chunk for ttl,
+// and their partial file is removed.
+func NewManager(dir string, ttl time.Duration) *Manager {
+	return &Manager{
+		dir:      dir,
+		ttl:      ttl,
+		sessions: make(map[string]*session),
+	}
+}
+
+// Create starts an upload session on behalf of owner. A non-zero expectedSize bounds the size of the upload
+// and must be reached before the session can be completed. A non-zero maxSize is the size
+// beyond which the upload is discarded.
+func (m *Manager) Create(owner string, expectedSize, maxSize uint64) (*Session, error) {
+	if maxSize > 0 && expectedSize > maxSize {
+		return nil, fmt.Errorf("%w: expected size %d is beyond the maximum of %d", ErrLimitExceeded, expectedSize, maxSize)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate session id: %s", err)
+	}
+
+	f, err := os.CreateTemp(m.dir, "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("could not create upload file: %s", err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return nil, fmt.Errorf("could not create upload file: %s", err)
+	}
+
+	s := session{
+		session: Session{
+			ID:           id,
+			Owner:        owner,
+			ExpectedSize: expectedSize,
+			MaxSize:      maxSize,
+			ExpiresAt:    time.Now().Add(m.ttl),
+		},
+		path: f.Name(),
+	}
+
+	m.mu.Lock()
+	m.sessions[id] = &s
+	m.mu.Unlock()
+
+	out := s.session
+	return &out, nil
+}
+
+// Get returns the session with the given ID.
+func (m *Manager) Get(id string) (*Session, error) {
+	s, err := m.lock(id)
+	if err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	out := s.session
+	return &out, nil
+}
+
+// Write appends data to the session. The data must start at the committed offset of the session.
+// On failure nothing is committed and the chunk can be sent again, unless the maximum size of the
+// session is exceeded: the session is discarded then.
+func (m *Manager) Write(id string, offset uint64, data []byte) (*Session, error) {
+	s, err := m.lock(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxSize := s.session.MaxSize; maxSize > 0 && offset+uint64(len(data)) > maxSize {
+		s.closed = true
+		s.mu.Unlock()
+
+		// The session lock is released before taking the manager lock, see lock
+		m.mu.Lock()
+		delete(m.sessions, id)
+		m.mu.Unlock()
+
+		os.Remove(s.path)
+		return nil, fmt.Errorf("%w: chunk ends at %d, the maximum size is %d", ErrLimitExceeded, offset+uint64(len(data)), maxSize)
+	}
+	defer s.mu.Unlock()
+
+	if offset != s.session.Offset {
+		return nil, fmt.Errorf("%w: chunk starts at %d, the committed offset is %d", ErrOffsetMismatch, offset, s.session.Offset)
+	}
+
+	size := offset + uint64(len(data))
+	if s.session.ExpectedSize > 0 && size > s.session.ExpectedSize {
+		return nil, fmt.Errorf("%w: chunk ends at %d, the expected size is %d", ErrTooLarge, size, s.session.ExpectedSize)
+	}
+
+	if err := writeAt(s.path, offset, data); err != nil {
+		return nil, fmt.Errorf("could not write chunk: %s", err)
+	}
+
+	s.session.Offset = size
+	s.session.ExpiresAt = time.Now().Add(m.ttl)
+
+	out := s.session
+	return &out, nil
+}
+
+// Complete ends the session and hands its file over to the caller, who is responsible for removing it.
+func (m *Manager) Complete(id string) (path string, size uint64, err error) {
+	s, err := m.lock(id)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if s.session.ExpectedSize > 0 && s.session.Offset != s.session.ExpectedSize {
+		s.mu.Unlock()
+		return "", 0, fmt.Errorf("%w: received %d of %d bytes", ErrIncomplete, s.session.Offset, s.session.ExpectedSize)
+	}
+
+	s.closed = true
+	path, size = s.path, s.session.Offset
+	s.mu.Unlock()
+
+	// The session lock is released before taking the manager lock, see lock
+	m.mu.Lock()
+	delete(m.sessions, id)
+	m.mu.Unlock()
+
+	return path, size, nil
+}
+
+// RemoveExpired removes the expired sessions and their partial files.
+func (m *Manager) RemoveExpired() {
+	m.mu.Lock()
+	var expired []*session
+	for id, s := range m.sessions {
+		s.mu.Lock()
+		if time.Now().After(s.session.ExpiresAt) {
+			delete(m.sessions, id)
+			s.closed = true
+			expired = append(expired, s)
+		}
+		s.mu.Unlock()
+	}
+	m.mu.Unlock()
+
+	for _, s := range expired {
+		os.Remove(s.path)
+	}
+}
+
+// lock returns the live session with the given ID, locked.
+// The manager lock may be held when taking a session lock, but is never taken while holding one.
+func (m *Manager) lock(id string) (*session, error) {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	m.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	s.mu.Lock()
+
+	// The session may have been completed or expired while waiting for the lock
+	if s.closed || time.Now().After(s.session.ExpiresAt) {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return s, nil
+}
+
+// writeAt writes data at offset of the file at path, truncating the file back to offset if it fails.
+func writeAt(path string, offset uint64, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.WriteAt(data, int64(offset)); err != nil {
+		f.Truncate(int64(offset))
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// newID returns a random session ID.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,120 @@
+package uploads
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager(t *testing.T) {
+	manager := NewManager(t.TempDir(), time.Hour)
+
+	session, err := manager.Create("", 10, 0)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, session.ID)
+	assert.Zero(t, session.Offset)
+	assert.Equal(t, uint64(10), session.ExpectedSize)
+
+	session, err = manager.Write(session.ID, 0, []byte("video"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), session.Offset)
+
+	// A chunk sent again after a dropped connection is rejected
+	_, err = manager.Write(session.ID, 0, []byte("video"))
+	require.ErrorIs(t, err, ErrOffsetMismatch)
+
+	_, err = manager.Write(session.ID, 5, []byte("Data!!"))
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	_, _, err = manager.Complete(session.ID)
+	require.ErrorIs(t, err, ErrIncomplete)
+
+	// Resume from the committed offset
+	got, err := manager.Get(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), got.Offset)
+
+	_, err = manager.Write(session.ID, got.Offset, []byte("Data!"))
+	require.NoError(t, err)
+
+	path, size, err := manager.Complete(session.ID)
+	require.NoError(t, err)
+	defer os.Remove(path)
+
+	assert.Equal(t, uint64(10), size)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "videoData!", string(data))
+
+	// A completed session is gone
+	_, err = manager.Get(session.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManagerUnknownSize(t *testing.T) {
+	manager := NewManager(t.TempDir(), time.Hour)
+
+	session, err := manager.Create("", 0, 0)
+	require.NoError(t, err)
+
+	_, err = manager.Write(session.ID, 0, []byte("videoData"))
+	require.NoError(t, err)
+
+	path, size, err := manager.Complete(session.ID)
+	require.NoError(t, err)
+	defer os.Remove(path)
+
+	assert.Equal(t, uint64(9), size)
+}
+
+func TestManagerLimitExceeded(t *testing.T) {
+	manager := NewManager(t.TempDir(), time.Hour)
+
+	_, err := manager.Create("", 20, 10)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	session, err := manager.Create("", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), session.MaxSize)
+
+	manager.mu.Lock()
+	path := manager.sessions[session.ID].path
+	manager.mu.Unlock()
+
+	_, err = manager.Write(session.ID, 0, []byte("video"))
+	require.NoError(t, err)
+
+	_, err = manager.Write(session.ID, 5, []byte("Data!!"))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	// The session is discarded along with its partial upload
+	_, err = manager.Get(session.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.NoFileExists(t, path)
+}
+
+func TestManagerRemoveExpired(t *testing.T) {
+	manager := NewManager(t.TempDir(), time.Millisecond)
+
+	session, err := manager.Create("", 0, 0)
+	require.NoError(t, err)
+
+	manager.mu.Lock()
+	path := manager.sessions[session.ID].path
+	manager.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = manager.Write(session.ID, 0, []byte("videoData"))
+	require.ErrorIs(t, err, ErrNotFound)
+
+	manager.RemoveExpired()
+
+	assert.NoFileExists(t, path)
+	assert.Empty(t, manager.sessions)
+}
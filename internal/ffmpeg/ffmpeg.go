@@ -0,0 +1,142 @@
+// Package ffmpeg implements the audiostripper commands on top of the ffmpeg and ffprobe binaries.
+package ffmpeg
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/sniff"
+)
+
+// waitDelay is how long a killed command is given to close its pipes, after which they are closed
+// from our side: a killed ffmpeg can't hold up the request on an input that is still being uploaded.
+const waitDelay = 5 * time.Second
+
+// safeDemuxers are the demuxers inputs of unknown format are probed among: those of the formats sniff tells,
+// none of which opens resources referenced by the input, unlike the playlist (hls, dash), concat or image
+// sequence demuxers.
+var safeDemuxers = strings.Join(sniff.Formats, ",")
+
+// encoding holds the ffmpeg settings used to produce an output format.
+type encoding struct {
+	codec   string
+	muxer   string
+	bitrate string // empty for lossless codecs
+}
+
+var encodings = map[audiostripper.Format]encoding{
+	audiostripper.FormatWAV:  {codec: "pcm_s16le", muxer: "wav"},
+	audiostripper.FormatFLAC: {codec: "flac", muxer: "flac"},
+	audiostripper.FormatMP3:  {codec: "libmp3lame", muxer: "mp3", bitrate: "128k"},
+	audiostripper.FormatOpus: {codec: "libopus", muxer: "ogg", bitrate: "64k"},
+	audiostripper.FormatAAC:  {codec: "aac", muxer: "ipod", bitrate: "128k"},
+}
+
+// channelArgs maps a channel layout to the ffmpeg arguments producing it.
+// ChannelsSource keeps the source layout and therefore adds no arguments.
+var channelArgs = map[audiostripper.ChannelLayout][]string{
+	audiostripper.ChannelsMono:       {"-ac", "1"},
+	audiostripper.ChannelsStereo:     {"-ac", "2"},
+	audiostripper.ChannelsSurround51: {"-ac", "6", "-channel_layout", "5.1"},
+}
+
+// Extract runs ffmpeg to extract the audio described by params, without resource limits.
+// ffmpeg and the processes it started are killed once the context of params is done.
+func Extract(params *audiostripper.ExtractCmdParams) error {
+	return Limits{}.Extract(params)
+}
+
+// Probe runs ffprobe to describe the media file in params, without resource limits.
+// ffprobe is killed once the context of params is done.
+func Probe(params *audiostripper.ProbeCmdParams) error {
+	return Limits{}.Probe(params)
+}
+
+// command returns the command running name with args, killed along with its process group once ctx is done.
+// A nil ctx is never done.
+func command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = waitDelay
+	killProcessGroup(cmd)
+	return cmd
+}
+
+// ProbeArgs returns the ffprobe arguments printing the JSON description of the file in params.
+func ProbeArgs(params *audiostripper.ProbeCmdParams) []string {
+	args := []string{"-v", "error", "-print_format", "json", "-show_format", "-show_streams"}
+	args = append(args, inputOptions("file", params.InputFormat)...)
+	return append(args, params.InputFile)
+}
+
+// inputOptions returns the options opening the input with the given protocol, and the given demuxer unless empty.
+// Nothing but the input itself can be opened: the input may be crafted to make the demuxer open local files
+// or network connections, which only the demuxers referencing external resources do and the protocols allow.
+func inputOptions(protocol, format string) []string {
+	if format == "" {
+		return []string{"-protocol_whitelist", protocol, "-format_whitelist", safeDemuxers}
+	}
+
+	// The demuxer is forced rather than picked by probing the input, when its format is known
+	return []string{"-protocol_whitelist", protocol, "-format_whitelist", format, "-f", format}
+}
+
+// ExtractArgs returns the ffmpeg arguments for the extraction described by params.
+// Progress is reported on stderr, see https://ffmpeg.org/ffmpeg.html#Advanced-options.
+func ExtractArgs(params *audiostripper.ExtractCmdParams) []string {
+	enc, ok := encodings[params.Format]
+	if !ok {
+		enc = encodings[audiostripper.FormatWAV]
+	}
+
+	args := []string{"-y", "-nostats", "-progress", "pipe:2"}
+
+	// Seeking on the input is both fast and accurate when transcoding, see https://trac.ffmpeg.org/wiki/Seeking
+	if params.Start > 0 {
+		args = append(args, "-ss", timestamp(params.Start))
+	}
+	if params.End > 0 {
+		args = append(args, "-to", timestamp(params.End))
+	}
+
+	protocol, input := "file", params.InputFile
+	if input == "" {
+		protocol, input = "pipe", "pipe:0"
+	}
+	args = append(args, inputOptions(protocol, params.InputFormat)...)
+	args = append(args, "-i", input)
+
+	if params.AudioStream != nil {
+		args = append(args, "-map", "0:a:"+strconv.Itoa(*params.AudioStream))
+	}
+
+	args = append(args, "-vn", "-acodec", enc.codec, "-ar", strconv.Itoa(params.SampleRate))
+	args = append(args, channelArgs[params.Channels]...)
+
+	if enc.bitrate != "" {
+		args = append(args, "-b:a", enc.bitrate)
+	}
+
+	output := params.OutputFile
+	if output == "" {
+		output = "pipe:1"
+
+		// The MPEG-4 muxer seeks back to write the index at the start of the file unless fragmented
+		if enc.muxer == "ipod" {
+			args = append(args, "-movflags", "frag_keyframe+empty_moov")
+		}
+	}
+	return append(args, "-f", enc.muxer, output)
+}
+
+// timestamp formats d in the ffmpeg time duration syntax.
+func timestamp(d time.Duration) string {
+	return strconv.FormatInt(d.Microseconds(), 10) + "us"
+}
@@ -0,0 +1,179 @@
+package ffmpeg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+)
+
+func TestExtractArgs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   audiostripper.Format
+		channels audiostripper.ChannelLayout
+		want     []string
+	}{
+		{
+			name:     "wav",
+			format:   audiostripper.FormatWAV,
+			channels: audiostripper.ChannelsStereo,
+			want:     []string{"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "-i", "in", "-vn", "-acodec", "pcm_s16le", "-ar", "48000", "-ac", "2", "-f", "wav", "out"},
+		},
+		{
+			name:     "flac",
+			format:   audiostripper.FormatFLAC,
+			channels: audiostripper.ChannelsStereo,
+			want:     []string{"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "-i", "in", "-vn", "-acodec", "flac", "-ar", "48000", "-ac", "2", "-f", "flac", "out"},
+		},
+		{
+			name:     "mp3",
+			format:   audiostripper.FormatMP3,
+			channels: audiostripper.ChannelsStereo,
+			want:     []string{"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "-i", "in", "-vn", "-acodec", "libmp3lame", "-ar", "48000", "-ac", "2", "-b:a", "128k", "-f", "mp3", "out"},
+		},
+		{
+			name:     "opus",
+			format:   audiostripper.FormatOpus,
+			channels: audiostripper.ChannelsStereo,
+			want:     []string{"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "-i", "in", "-vn", "-acodec", "libopus", "-ar", "48000", "-ac", "2", "-b:a", "64k", "-f", "ogg", "out"},
+		},
+		{
+			name:     "aac",
+			format:   audiostripper.FormatAAC,
+			channels: audiostripper.ChannelsStereo,
+			want:     []string{"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "-i", "in", "-vn", "-acodec", "aac", "-ar", "48000", "-ac", "2", "-b:a", "128k", "-f", "ipod", "out"},
+		},
+		{
+			name:     "mono",
+			format:   audiostripper.FormatWAV,
+			channels: audiostripper.ChannelsMono,
+			want:     []string{"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "-i", "in", "-vn", "-acodec", "pcm_s16le", "-ar", "48000", "-ac", "1", "-f", "wav", "out"},
+		},
+		{
+			name:     "surround 5.1",
+			format:   audiostripper.FormatFLAC,
+			channels: audiostripper.ChannelsSurround51,
+			want:     []string{"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "-i", "in", "-vn", "-acodec", "flac", "-ar", "48000", "-ac", "6", "-channel_layout", "5.1", "-f", "flac", "out"},
+		},
+		{
+			name:     "source layout",
+			format:   audiostripper.FormatWAV,
+			channels: audiostripper.ChannelsSource,
+			want:     []string{"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "-i", "in", "-vn", "-acodec", "pcm_s16le", "-ar", "48000", "-f", "wav", "out"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ExtractArgs(&audiostripper.ExtractCmdParams{
+				InputFile:  "in",
+				OutputFile: "out",
+				SampleRate: 48000,
+				Format:     tc.format,
+				Channels:   tc.channels,
+			})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestExtractArgsTrim(t *testing.T) {
+	got := ExtractArgs(&audiostripper.ExtractCmdParams{
+		InputFile:  "in",
+		OutputFile: "out",
+		SampleRate: 16000,
+		Format:     audiostripper.FormatWAV,
+		Channels:   audiostripper.ChannelsMono,
+		Start:      1500 * time.Millisecond,
+		End:        time.Minute,
+	})
+
+	assert.Equal(t, []string{
+		"-y", "-nostats", "-progress", "pipe:2", "-ss", "1500000us", "-to", "60000000us",
+		"-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "-i", "in", "-vn", "-acodec", "pcm_s16le", "-ar", "16000", "-ac", "1", "-f", "wav", "out",
+	}, got)
+}
+
+func TestExtractArgsAudioStream(t *testing.T) {
+	stream := 2
+
+	got := ExtractArgs(&audiostripper.ExtractCmdParams{
+		InputFile:   "in",
+		OutputFile:  "out",
+		SampleRate:  48000,
+		Format:      audiostripper.FormatWAV,
+		Channels:    audiostripper.ChannelsStereo,
+		AudioStream: &stream,
+	})
+
+	assert.Equal(t, []string{
+		"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "-i", "in", "-map", "0:a:2",
+		"-vn", "-acodec", "pcm_s16le", "-ar", "48000", "-ac", "2", "-f", "wav", "out",
+	}, got)
+}
+
+func TestExtractArgsInputFormat(t *testing.T) {
+	got := ExtractArgs(&audiostripper.ExtractCmdParams{
+		InputFile:   "in",
+		InputFormat: "matroska",
+		OutputFile:  "out",
+		SampleRate:  48000,
+		Format:      audiostripper.FormatWAV,
+		Channels:    audiostripper.ChannelsStereo,
+	})
+
+	assert.Equal(t, []string{
+		"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "file", "-format_whitelist", "matroska", "-f", "matroska", "-i", "in",
+		"-vn", "-acodec", "pcm_s16le", "-ar", "48000", "-ac", "2", "-f", "wav", "out",
+	}, got)
+}
+
+func TestExtractArgsPipes(t *testing.T) {
+	testCases := []struct {
+		name   string
+		format audiostripper.Format
+		want   []string
+	}{
+		{
+			name:   "wav",
+			format: audiostripper.FormatWAV,
+			want: []string{
+				"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "pipe", "-format_whitelist", safeDemuxers, "-i", "pipe:0",
+				"-vn", "-acodec", "pcm_s16le", "-ar", "48000", "-ac", "2", "-f", "wav", "pipe:1",
+			},
+		},
+		{
+			name:   "aac",
+			format: audiostripper.FormatAAC,
+			want: []string{
+				"-y", "-nostats", "-progress", "pipe:2", "-protocol_whitelist", "pipe", "-format_whitelist", safeDemuxers, "-i", "pipe:0",
+				"-vn", "-acodec", "aac", "-ar", "48000", "-ac", "2", "-b:a", "128k",
+				"-movflags", "frag_keyframe+empty_moov", "-f", "ipod", "pipe:1",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ExtractArgs(&audiostripper.ExtractCmdParams{
+				SampleRate: 48000,
+				Format:     tc.format,
+				Channels:   audiostripper.ChannelsStereo,
+			})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestProbeArgs(t *testing.T) {
+	got := ProbeArgs(&audiostripper.ProbeCmdParams{InputFile: "in"})
+	assert.Equal(t, []string{"-v", "error", "-print_format", "json", "-show_format", "-show_streams", "-protocol_whitelist", "file", "-format_whitelist", safeDemuxers, "in"}, got)
+
+	t.Run("input format", func(t *testing.T) {
+		got := ProbeArgs(&audiostripper.ProbeCmdParams{InputFile: "in", InputFormat: "mov"})
+		assert.Equal(t, []string{"-v", "error", "-print_format", "json", "-show_format", "-show_streams", "-protocol_whitelist", "file", "-format_whitelist", "mov", "-f", "mov", "in"}, got)
+	})
+}
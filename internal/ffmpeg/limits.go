@@ -0,0 +1,59 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+)
+
+// Limits are the resource limits the commands run with, so that a hostile input can't exhaust the host.
+// Zero values mean no limit. They are only enforced on Linux.
+type Limits struct {
+	CPUTime   time.Duration // CPU time, across all threads
+	Memory    uint64        // Size of the address space in bytes
+	FileSize  uint64        // Size of the largest file written in bytes
+	OpenFiles uint64        // Number of open file descriptors
+}
+
+// Extract runs ffmpeg with the limits to extract the audio described by params.
+// ffmpeg and the processes it started are killed once the context of params is done.
+func (l Limits) Extract(params *audiostripper.ExtractCmdParams) error {
+	cmd := l.command(params.Context, "ffmpeg", ExtractArgs(params)...)
+	cmd.Stdin = params.Stdin
+	cmd.Stdout = params.Stdout
+	cmd.Stderr = params.Stderr
+	return l.run(cmd)
+}
+
+// Probe runs ffprobe with the limits to describe the media file in params.
+// ffprobe is killed once the context of params is done.
+func (l Limits) Probe(params *audiostripper.ProbeCmdParams) error {
+	cmd := l.command(params.Context, "ffprobe", ProbeArgs(params)...)
+	cmd.Stdout = params.Stdout
+	cmd.Stderr = params.Stderr
+	return l.run(cmd)
+}
+
+// command returns the command running name with args under the limits.
+// A shell sets them before exec'ing the command, which starts limited and passes them on to the processes it starts.
+// The shell exits without running the command if a limit can't be set, not to leave it running unchecked.
+func (l Limits) command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	script := ulimits(l)
+	if script == "" {
+		return command(ctx, name, args...)
+	}
+	return command(ctx, "/bin/sh", append([]string{"-c", script + `exec "$0" "$@"`, name}, args...)...)
+}
+
+// run runs cmd and waits for it to complete.
+// A command killed for going beyond its limits fails with audiostripper.ErrResourceLimit.
+func (l Limits) run(cmd *exec.Cmd) error {
+	err := cmd.Run()
+	if limitSignaled(err) {
+		return fmt.Errorf("%w: %w", audiostripper.ErrResourceLimit, err)
+	}
+	return err
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package ffmpeg
+
+import "os/exec"
+
+// killProcessGroup leaves the default cancellation of cmd, killing the process alone,
+// on the platforms without process groups.
+func killProcessGroup(cmd *exec.Cmd) {}
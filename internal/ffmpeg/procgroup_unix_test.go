@@ -0,0 +1,48 @@
+//go:build unix
+
+package ffmpeg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandKillsProcessGroup(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The shell starts a child of its own, as ffmpeg may, and waits for it
+	cmd := command(ctx, "sh", "-c", "sleep 60 & echo $! > "+pidFile+"; wait")
+	require.NoError(t, cmd.Start())
+
+	var child int
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(pidFile)
+		if err != nil || len(data) == 0 || data[len(data)-1] != '\n' {
+			return false
+		}
+		child, err = strconv.Atoi(string(data[:len(data)-1]))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	start := time.Now()
+	cancel()
+
+	require.Error(t, cmd.Wait())
+	assert.Less(t, time.Since(start), waitDelay)
+
+	// Killed along with the shell, the child is gone once reaped by init
+	assert.Eventually(t, func() bool {
+		return errors.Is(syscall.Kill(child, 0), syscall.ESRCH)
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,53 @@
+package ffmpeg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// ulimits returns the shell commands setting the limits l, run by the shell before it execs the command.
+// The CPU time limit is soft, one second short of the hard one, for the kernel to send SIGXCPU rather than SIGKILL.
+// It is set first, the hard limit can't go below the soft one.
+func ulimits(l Limits) string {
+	var b strings.Builder
+
+	if seconds := uint64(math.Ceil(l.CPUTime.Seconds())); seconds > 0 {
+		fmt.Fprintf(&b, "ulimit -S -t %d && ulimit -H -t %d && ", seconds, seconds+1)
+	}
+
+	limits := []struct {
+		option string
+		value  uint64
+	}{
+		{option: "-v", value: ceilDiv(l.Memory, 1024)},  // KiB
+		{option: "-f", value: ceilDiv(l.FileSize, 512)}, // 512-byte blocks, as POSIX has it
+		{option: "-n", value: l.OpenFiles},
+	}
+
+	for _, limit := range limits {
+		if limit.value > 0 {
+			fmt.Fprintf(&b, "ulimit %s %d && ", limit.option, limit.value)
+		}
+	}
+	return b.String()
+}
+
+// ceilDiv returns n divided by d, rounded up.
+func ceilDiv(n, d uint64) uint64 {
+	return (n + d - 1) / d
+}
+
+// limitSignaled reports whether err is the exit of a command killed for going beyond its CPU time or file size limit.
+func limitSignaled(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+
+	ws, ok := exitErr.Sys().(syscall.WaitStatus)
+	return ok && ws.Signaled() && (ws.Signal() == syscall.SIGXCPU || ws.Signal() == syscall.SIGXFSZ)
+}
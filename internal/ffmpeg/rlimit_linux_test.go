@@ -0,0 +1,63 @@
+package ffmpeg
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+)
+
+func TestLimitsCommand(t *testing.T) {
+	limits := Limits{
+		CPUTime:   1500 * time.Millisecond,
+		Memory:    1 << 30,
+		FileSize:  1 << 20,
+		OpenFiles: 32,
+	}
+
+	// The command reads its own limits, set before it started
+	cmd := limits.command(context.Background(), "cat", "/proc/self/limits")
+	out, err := cmd.Output()
+	require.NoError(t, err)
+
+	assert.Regexp(t, `Max cpu time\s+2\s+3\s+seconds`, string(out))
+	assert.Regexp(t, `Max address space\s+1073741824\s+1073741824\s+bytes`, string(out))
+	assert.Regexp(t, `Max file size\s+1048576\s+1048576\s+bytes`, string(out))
+	assert.Regexp(t, `Max open files\s+32\s+32\s+files`, string(out))
+}
+
+func TestLimitsCommandUnlimited(t *testing.T) {
+	cmd := Limits{}.command(context.Background(), "cat", "/proc/self/limits")
+	assert.Equal(t, []string{"cat", "/proc/self/limits"}, cmd.Args)
+}
+
+func TestLimitsCommandArgs(t *testing.T) {
+	// The arguments reach the command untouched by the shell
+	cmd := Limits{OpenFiles: 32}.command(context.Background(), "echo", "a  b", "$HOME", "'c'")
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "a  b $HOME 'c'\n", string(out))
+}
+
+func TestLimitsRun(t *testing.T) {
+	// The file size limit kills the command writing beyond it right away
+	out := t.TempDir() + "/out"
+	cmd := Limits{FileSize: 1024}.command(context.Background(), "head", "-c", "4096", "/dev/zero")
+
+	f, err := os.Create(out)
+	require.NoError(t, err)
+	defer f.Close()
+	cmd.Stdout = f
+
+	err = Limits{FileSize: 1024}.run(cmd)
+	require.ErrorIs(t, err, audiostripper.ErrResourceLimit)
+
+	info, err := os.Stat(out)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1024, info.Size())
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package ffmpeg
+
+// ulimits returns no command, the limits are only set on Linux.
+func ulimits(l Limits) string {
+	return ""
+}
+
+// limitSignaled reports false, the commands being unlimited.
+func limitSignaled(err error) bool {
+	return false
+}
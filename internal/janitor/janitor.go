@@ -0,0 +1,49 @@
+// Package janitor removes the files left behind in a work directory, by a crash or a bug.
+package janitor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Sweep removes the entries of dir last modified more than maxAge ago, and returns their paths.
+// Files still in use are written to regularly, or short-lived, so maxAge must exceed both the time
+// a file can go untouched while in use and the lifetime of the upload sessions stored in dir.
+// It keeps sweeping past the entries it fails to remove, and returns the failures joined.
+func Sweep(dir string, maxAge time.Duration) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		removed []string
+		errs    []error
+		cutoff  = time.Now().Add(-maxAge)
+	)
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue // Removed since the directory was read
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if info.ModTime().After(cutoff) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if err := os.RemoveAll(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, path)
+	}
+	return removed, errors.Join(errs...)
+}
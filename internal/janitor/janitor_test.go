@@ -0,0 +1,43 @@
+package janitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweep(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		orphan   = filepath.Join(dir, "input-1")
+		orphans  = filepath.Join(dir, "leftovers")
+		inUse    = filepath.Join(dir, "upload-2")
+		longTime = time.Now().Add(-2 * time.Hour)
+	)
+
+	require.NoError(t, os.WriteFile(orphan, []byte("videoData"), 0o600))
+	require.NoError(t, os.Chtimes(orphan, longTime, longTime))
+
+	require.NoError(t, os.Mkdir(orphans, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(orphans, "input-3.wav"), []byte("audioData"), 0o600))
+	require.NoError(t, os.Chtimes(orphans, longTime, longTime))
+
+	require.NoError(t, os.WriteFile(inUse, []byte("video"), 0o600))
+
+	removed, err := Sweep(dir, time.Hour)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{orphan, orphans}, removed)
+	assert.NoFileExists(t, orphan)
+	assert.NoDirExists(t, orphans)
+	assert.FileExists(t, inUse)
+}
+
+func TestSweepMissingDir(t *testing.T) {
+	_, err := Sweep(filepath.Join(t.TempDir(), "missing"), time.Hour)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,169 @@
+// Package jobstore implements the jobs store on top of a bbolt database file.
+package jobstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/app/jobs"
+)
+
+var jobsBucket = []byte("jobs")
+
+// Store persists jobs in a bbolt database.
+type Store struct {
+	db *bolt.DB
+}
+
+// record is the stored representation of a job.
+// The progress of a job is transient and not stored.
+type record struct {
+	ID        string                            `json:"id"`
+	Owner     string                            `json:"owner,omitempty"`
+	State     jobs.State                        `json:"state"`
+	Input     input                             `json:"input"`
+	Metadata  map[string]string                 `json:"metadata,omitempty"`
+	Output    *audiostripper.ExtractAudioOutput `json:"output,omitempty"`
+	Err       string                            `json:"error,omitempty"`
+	CreatedAt time.Time                         `json:"created_at"`
+	UpdatedAt time.Time                         `json:"updated_at"`
+	ExpiresAt time.Time                         `json:"expires_at"`
+}
+
+// input is the stored representation of the extraction input of a job.
+type input struct {
+	FilePath    string                        `json:"file_path"`
+	InputFormat string                        `json:"input_format,omitempty"`
+	SampleRate  int                           `json:"sample_rate"`
+	Format      audiostripper.Format          `json:"format"`
+	Channels    audiostripper.ChannelLayout   `json:"channels"`
+	Start       time.Duration                 `json:"start,omitempty"`
+	End         time.Duration                 `json:"end,omitempty"`
+	Stream      *audiostripper.StreamSelector `json:"stream,omitempty"`
+	AllStreams  bool                          `json:"all_streams,omitempty"`
+}
+
+// Open opens the database at path, creating it if needed.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open database: %s", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(jobsBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not create jobs bucket: %s", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save stores the job, replacing any previous version of it.
+func (s *Store) Save(job *jobs.Job) error {
+	data, err := json.Marshal(newRecord(job))
+	if err != nil {
+		return fmt.Errorf("could not encode job: %s", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(job.ID), data)
+	})
+}
+
+// Load returns every stored job.
+func (s *Store) Load() ([]*jobs.Job, error) {
+	var out []*jobs.Job
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(k, v []byte) error {
+			var r record
+			if err := json.Unmarshal(v, &r); err != nil {
+				return fmt.Errorf("could not decode job %s: %s", k, err)
+			}
+
+			out = append(out, r.job())
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete removes the job with the given ID, if stored.
+func (s *Store) Delete(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Delete([]byte(id))
+	})
+}
+
+func newRecord(job *jobs.Job) *record {
+	r := record{
+		ID:    job.ID,
+		Owner: job.Owner,
+		State: job.State,
+		Input: input{
+			FilePath:    job.Input.FilePath,
+			InputFormat: job.Input.InputFormat,
+			SampleRate:  job.Input.SampleRate,
+			Format:      job.Input.Format,
+			Channels:    job.Input.Channels,
+			Start:       job.Input.Start,
+			End:         job.Input.End,
+			Stream:      job.Input.Stream,
+			AllStreams:  job.Input.AllStreams,
+		},
+		Metadata:  job.Metadata,
+		Output:    job.Output,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+		ExpiresAt: job.ExpiresAt,
+	}
+
+	if job.Err != nil {
+		r.Err = job.Err.Error()
+	}
+	return &r
+}
+
+func (r *record) job() *jobs.Job {
+	job := jobs.Job{
+		ID:    r.ID,
+		Owner: r.Owner,
+		State: r.State,
+		Input: audiostripper.ExtractAudioInput{
+			FilePath:    r.Input.FilePath,
+			InputFormat: r.Input.InputFormat,
+			SampleRate:  r.Input.SampleRate,
+			Format:      r.Input.Format,
+			Channels:    r.Input.Channels,
+			Start:       r.Input.Start,
+			End:         r.Input.End,
+			Stream:      r.Input.Stream,
+			AllStreams:  r.Input.AllStreams,
+		},
+		Metadata:  r.Metadata,
+		Output:    r.Output,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+		ExpiresAt: r.ExpiresAt,
+	}
+
+	if r.Err != "" {
+		job.Err = errors.New(r.Err)
+	}
+	return &job
+}
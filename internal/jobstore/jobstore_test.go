@@ -0,0 +1,95 @@
+package jobstore
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alesr/audiostrippersvc/internal/app/audiostripper"
+	"github.com/alesr/audiostrippersvc/internal/app/jobs"
+	"github.com/alesr/audiostrippersvc/internal/sniff"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+
+	store, err := Open(path)
+	require.NoError(t, err)
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	running := jobs.Job{
+		ID:    "running",
+		Owner: "owner",
+		State: jobs.StateRunning,
+		Input: audiostripper.ExtractAudioInput{
+			FilePath:    "/data/running.mp4",
+			InputFormat: sniff.FormatMOV,
+			SampleRate:  48000,
+			Format:      audiostripper.FormatOpus,
+			Channels:    audiostripper.ChannelsMono,
+			Start:       time.Second,
+			End:         time.Minute,
+			Stream:      &audiostripper.StreamSelector{Language: "eng"},
+		},
+		Metadata:  map[string]string{"request_id": "1"},
+		Progress:  audiostripper.Progress{OutTime: time.Second},
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+
+	failed := jobs.Job{
+		ID:        "failed",
+		State:     jobs.StateFailed,
+		Err:       errors.New("ffmpeg failed"),
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+
+	require.NoError(t, store.Save(&running))
+	require.NoError(t, store.Save(&failed))
+
+	// Saving again replaces the job
+	running.State = jobs.StateSucceeded
+	running.ExpiresAt = created.Add(24 * time.Hour)
+	running.Output = &audiostripper.ExtractAudioOutput{
+		FilePath: "/data/running.ogg",
+		Media:    &audiostripper.MediaInfo{FormatName: "ogg", Duration: 59 * time.Second},
+	}
+	require.NoError(t, store.Save(&running))
+
+	require.NoError(t, store.Close())
+
+	// Jobs survive reopening the database
+	store, err = Open(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	got, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	// Jobs are loaded in key order, the progress is not stored
+	expected := running
+	expected.Progress = audiostripper.Progress{}
+
+	assert.Equal(t, &failed, got[0])
+	assert.Equal(t, &expected, got[1])
+
+	// Recovered jobs keep the sniffed format of their video, passed to ffmpeg rather than probed
+	assert.Equal(t, sniff.FormatMOV, got[1].Input.InputFormat)
+
+	require.NoError(t, store.Delete(failed.ID))
+
+	got, err = store.Load()
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, running.ID, got[0].ID)
+
+	// Deleting a job that is not stored is not an error
+	require.NoError(t, store.Delete(failed.ID))
+}
@@ -0,0 +1,96 @@
+package sniff
+
+import (
+	"bytes"
+	"errors"
+)
+
+// Container formats told by Format, named after the ffmpeg demuxers reading them.
+const (
+	FormatMOV      = "mov"      // ISO base media files: MP4, MOV, M4A, 3GP...
+	FormatMatroska = "matroska" // Matroska and WebM
+	FormatAVI      = "avi"
+	FormatMPEGTS   = "mpegts" // MPEG transport stream
+	FormatMPEGPS   = "mpeg"   // MPEG program stream, as in VOB files
+	FormatFLV      = "flv"
+	FormatWAV      = "wav"
+	FormatMP3      = "mp3"
+	FormatOgg      = "ogg"
+	FormatFLAC     = "flac"
+)
+
+// tsPacketSize is the size of an MPEG transport stream packet, each starting with a sync byte.
+const tsPacketSize = 188
+
+// HeadSize is the number of bytes Format needs at most to tell a format.
+const HeadSize = tsPacketSize + 1
+
+var (
+	// Formats are the formats Format tells.
+	Formats = []string{FormatMOV, FormatMatroska, FormatAVI, FormatMPEGTS, FormatMPEGPS, FormatFLV, FormatWAV, FormatMP3, FormatOgg, FormatFLAC}
+
+	// ErrUnknownFormat is returned when the format of a file is none of Formats.
+	ErrUnknownFormat = errors.New("unknown format")
+)
+
+// isoBoxTypes are the types of the boxes an ISO base media file may start with.
+// Files predating the ftyp box, such as older QuickTime movies, start with one of the others.
+var isoBoxTypes = []string{"ftyp", "moov", "mdat", "wide", "free"}
+
+// Format tells the container format of the media file starting with head by its magic bytes.
+// It fails with ErrShortHead when head is too short to tell, which never happens with HeadSize bytes.
+func Format(head []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(head, []byte{0x1a, 0x45, 0xdf, 0xa3}): // EBML header
+		return FormatMatroska, nil
+	case bytes.HasPrefix(head, []byte{0x00, 0x00, 0x01, 0xba}): // Pack header
+		return FormatMPEGPS, nil
+	case bytes.HasPrefix(head, []byte("FLV\x01")):
+		return FormatFLV, nil
+	case bytes.HasPrefix(head, []byte("OggS")):
+		return FormatOgg, nil
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		return FormatFLAC, nil
+	case bytes.HasPrefix(head, []byte("ID3")), isMPEGAudioFrame(head):
+		return FormatMP3, nil
+	}
+
+	if len(head) < 12 {
+		return "", ErrShortHead
+	}
+
+	if bytes.HasPrefix(head, []byte("RIFF")) {
+		switch string(head[8:12]) {
+		case "AVI ":
+			return FormatAVI, nil
+		case "WAVE":
+			return FormatWAV, nil
+		}
+		return "", ErrUnknownFormat
+	}
+
+	for _, boxType := range isoBoxTypes {
+		if string(head[4:8]) == boxType {
+			return FormatMOV, nil
+		}
+	}
+
+	// A single sync byte is too common to be conclusive, the next packet must start with one as well
+	if head[0] == 0x47 {
+		if len(head) < HeadSize {
+			return "", ErrShortHead
+		}
+		if head[tsPacketSize] == 0x47 {
+			return FormatMPEGTS, nil
+		}
+	}
+	return "", ErrUnknownFormat
+}
+
+// isMPEGAudioFrame reports whether head starts with the header of an MPEG audio layer I, II or III frame,
+// as MP3 files without ID3 tag do.
+func isMPEGAudioFrame(head []byte) bool {
+	// 11 bits of frame sync, then the version, with 01 reserved, and the layer, with 00 reserved
+	return len(head) >= 2 && head[0] == 0xff && head[1]&0xe0 == 0xe0 &&
+		head[1]&0x18 != 0x08 && head[1]&0x06 != 0
+}
@@ -0,0 +1,127 @@
+package sniff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		head     []byte
+		expected string
+		err      error
+	}{
+		{
+			name:     "mp4",
+			head:     concat(box("ftyp", 16), box("moov", 1024)[:16]),
+			expected: FormatMOV,
+		},
+		{
+			name:     "quicktime without ftyp",
+			head:     box("wide", 16),
+			expected: FormatMOV,
+		},
+		{
+			name:     "webm",
+			head:     []byte{0x1a, 0x45, 0xdf, 0xa3, 0x9f, 0x42, 0x86, 0x81, 0x01, 0x42, 0xf7, 0x81},
+			expected: FormatMatroska,
+		},
+		{
+			name:     "avi",
+			head:     []byte("RIFF\x00\x10\x00\x00AVI LIST"),
+			expected: FormatAVI,
+		},
+		{
+			name:     "wav",
+			head:     []byte("RIFF\x24\x08\x00\x00WAVEfmt "),
+			expected: FormatWAV,
+		},
+		{
+			name: "unknown riff",
+			head: []byte("RIFF\x24\x08\x00\x00WEBPVP8 "),
+			err:  ErrUnknownFormat,
+		},
+		{
+			name:     "mpeg transport stream",
+			head:     concat(tsPacket(), tsPacket()[:1]),
+			expected: FormatMPEGTS,
+		},
+		{
+			name: "mpeg transport stream cut before the second packet",
+			head: tsPacket(),
+			err:  ErrShortHead,
+		},
+		{
+			name: "single sync byte",
+			head: concat([]byte{0x47}, make([]byte, tsPacketSize)),
+			err:  ErrUnknownFormat,
+		},
+		{
+			name:     "mpeg program stream",
+			head:     []byte{0x00, 0x00, 0x01, 0xba, 0x44, 0x00, 0x04, 0x00, 0x04, 0x01, 0x01, 0x89},
+			expected: FormatMPEGPS,
+		},
+		{
+			name:     "flv",
+			head:     []byte("FLV\x01\x05\x00\x00\x00\x09\x00\x00\x00"),
+			expected: FormatFLV,
+		},
+		{
+			name:     "mp3 with id3 tag",
+			head:     []byte("ID3\x04\x00\x00\x00\x00\x00\x23TSSE"),
+			expected: FormatMP3,
+		},
+		{
+			name:     "mp3 frame",
+			head:     []byte{0xff, 0xfb, 0x90, 0x64},
+			expected: FormatMP3,
+		},
+		{
+			name: "adts frame",
+			head: []byte{0xff, 0xf1, 0x50, 0x80, 0x02, 0x1f, 0xfc, 0x21, 0x00, 0x49, 0x90, 0x02},
+			err:  ErrUnknownFormat,
+		},
+		{
+			name:     "ogg",
+			head:     []byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00"),
+			expected: FormatOgg,
+		},
+		{
+			name:     "flac",
+			head:     []byte("fLaC\x00\x00\x00\x22"),
+			expected: FormatFLAC,
+		},
+		{
+			name: "text",
+			head: []byte("#!/bin/sh\nrm -rf /\n"),
+			err:  ErrUnknownFormat,
+		},
+		{
+			name: "too short",
+			head: []byte("RIFF"),
+			err:  ErrShortHead,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Format(tc.head)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+// tsPacket returns an MPEG transport stream packet filled with stuffing.
+func tsPacket() []byte {
+	return concat([]byte{0x47, 0x1f, 0xff, 0x10}, bytes.Repeat([]byte{0xff}, tsPacketSize-4))
+}
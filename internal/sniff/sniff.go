@@ -0,0 +1,69 @@
+// Package sniff inspects the first bytes of a media file to tell how it can be demuxed.
+package sniff
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+)
+
+// ErrShortHead is returned when more bytes of the file are needed to decide.
+var ErrShortHead = errors.New("not enough data")
+
+// NeedsSeeking reports whether the media file starting with head can only be demuxed from a seekable input.
+//
+// This is the case of ISO base media files (MP4, MOV, M4A...) whose moov box, indexing the media data,
+// comes after the mdat box holding it: the demuxer has to read the end of the file before the beginning.
+// Other containers can be demuxed as they are read.
+func NeedsSeeking(head []byte) (bool, error) {
+	if len(head) < 8 {
+		return false, ErrShortHead
+	}
+
+	// Older QuickTime movies have no ftyp box, starting with any of the others
+	if !slices.Contains(isoBoxTypes, string(head[4:8])) {
+		return false, nil
+	}
+
+	// Walk the top level boxes until either the moov or the mdat box shows up,
+	// see ISO/IEC 14496-12 section 4.2
+	for offset := uint64(0); ; {
+		if uint64(len(head))-offset < 8 {
+			return false, ErrShortHead
+		}
+
+		size := uint64(binary.BigEndian.Uint32(head[offset:]))
+		boxType := string(head[offset+4 : offset+8])
+
+		switch boxType {
+		case "moov":
+			return false, nil
+		case "mdat":
+			return true, nil
+		}
+
+		headerSize := uint64(8)
+
+		switch size {
+		case 0: // The box extends to the end of the file
+			return true, nil
+		case 1: // The size is in the 64 bits following the type
+			if uint64(len(head))-offset < 16 {
+				return false, ErrShortHead
+			}
+			size = binary.BigEndian.Uint64(head[offset+8:])
+			headerSize = 16
+		}
+
+		// A malformed box, leave it to the demuxer to report it
+		if size < headerSize {
+			return true, nil
+		}
+
+		// The next box starts beyond head. Checked before moving to it, the size being the file's to choose
+		if size >= uint64(len(head))-offset {
+			return false, ErrShortHead
+		}
+		offset += size
+	}
+}
@@ -0,0 +1,126 @@
+package sniff
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeedsSeeking(t *testing.T) {
+	testCases := []struct {
+		name     string
+		head     []byte
+		expected bool
+		err      error
+	}{
+		{
+			name:     "matroska",
+			head:     []byte{0x1a, 0x45, 0xdf, 0xa3, 0x9f, 0x42, 0x86, 0x81, 0x01},
+			expected: false,
+		},
+		{
+			name:     "mp4 with moov first",
+			head:     concat(box("ftyp", 16), box("free", 8), box("moov", 1024)[:16]),
+			expected: false,
+		},
+		{
+			name:     "mp4 with mdat first",
+			head:     concat(box("ftyp", 16), box("mdat", 1<<20)[:16]),
+			expected: true,
+		},
+		{
+			name:     "mp4 with a 64 bits box size",
+			head:     concat(box("ftyp", 16), largeBox("wide", 24), box("mdat", 1<<20)[:8]),
+			expected: true,
+		},
+		{
+			name: "mp4 cut before the moov box",
+			head: concat(box("ftyp", 16), box("free", 32)[:12]),
+			err:  ErrShortHead,
+		},
+		{
+			name:     "quicktime with mdat first",
+			head:     concat(box("wide", 8), box("mdat", 1<<20)[:16]),
+			expected: true,
+		},
+		{
+			name:     "quicktime with moov first",
+			head:     concat(box("free", 16), box("moov", 1024)[:16]),
+			expected: false,
+		},
+		{
+			name: "64 bits box size wrapping around",
+			head: concat(box("ftyp", 16), largeBoxSize("free", 1<<64-16), box("mdat", 1<<20)[:16]),
+			err:  ErrShortHead,
+		},
+		{
+			name: "64 bits box size beyond the head",
+			head: concat(box("ftyp", 16), largeBoxSize("free", 1<<64-32), box("mdat", 1<<20)[:16]),
+			err:  ErrShortHead,
+		},
+		{
+			name:     "malformed 64 bits box size",
+			head:     concat(box("ftyp", 16), largeBoxSize("free", 12), box("mdat", 1<<20)[:16]),
+			expected: true,
+		},
+		{
+			name: "too short",
+			head: []byte{0x00, 0x00, 0x00},
+			err:  ErrShortHead,
+		},
+		{
+			name:     "malformed mp4",
+			head:     concat(box("ftyp", 16), []byte{0, 0, 0, 4, 'f', 'r', 'e', 'e'}),
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NeedsSeeking(tc.head)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+// box returns an ISO base media box of the given type and size, filled with zeros.
+func box(boxType string, size int) []byte {
+	b := make([]byte, size)
+	binary.BigEndian.PutUint32(b, uint32(size))
+	copy(b[4:], boxType)
+	return b
+}
+
+// largeBox returns a box of the given type and size using the 64 bits size field.
+func largeBox(boxType string, size int) []byte {
+	b := make([]byte, size)
+	binary.BigEndian.PutUint32(b, 1)
+	copy(b[4:], boxType)
+	binary.BigEndian.PutUint64(b[8:], uint64(size))
+	return b
+}
+
+// largeBoxSize returns the header of a box of the given type declaring size in its 64 bits size field.
+func largeBoxSize(boxType string, size uint64) []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint32(b, 1)
+	copy(b[4:], boxType)
+	binary.BigEndian.PutUint64(b[8:], size)
+	return b
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+// Package workers bounds the number of extractions running at once.
+package workers
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQueueFull is returned by Acquire when too many callers are already waiting for a worker.
+var ErrQueueFull = errors.New("queue full")
+
+type (
+	// Pool hands out a fixed number of workers to the callers, in no particular order.
+	Pool struct {
+		slots    chan struct{}
+		maxQueue int
+
+		mu      sync.Mutex
+		queued  int // Callers of Acquire waiting for a worker
+		waiting int // Callers of Wait waiting for a worker
+	}
+
+	// Stats is a snapshot of the use of a pool.
+	Stats struct {
+		Workers int
+		Running int // Workers in use
+		Queued  int // Callers of Acquire waiting for a worker, bounded by the maximum queue length
+		Waiting int // Callers of Wait waiting for a worker
+	}
+)
+
+// NewPool creates a pool of the given number of workers, with at most maxQueue callers of Acquire waiting.
+func NewPool(workers, maxQueue int) *Pool {
+	return &Pool{
+		slots:    make(chan struct{}, workers),
+		maxQueue: maxQueue,
+	}
+}
+
+// Acquire waits for a free worker and returns the function releasing it, to be called once done.
+// It fails with ErrQueueFull rather than waiting behind maxQueue other callers, and with the error
+// of ctx if it is done first.
+func (p *Pool) Acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case p.slots <- struct{}{}:
+		return p.releaser(), nil
+	default:
+	}
+
+	p.mu.Lock()
+	if p.queued >= p.maxQueue {
+		p.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	p.queued++
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.queued--
+		p.mu.Unlock()
+	}()
+
+	return p.wait(ctx)
+}
+
+// Wait waits for a free worker like Acquire, but as long as needed: it is meant for the work
+// that is queued already, such as asynchronous jobs.
+func (p *Pool) Wait(ctx context.Context) (release func(), err error) {
+	p.mu.Lock()
+	p.waiting++
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.waiting--
+		p.mu.Unlock()
+	}()
+
+	return p.wait(ctx)
+}
+
+// Stats returns the current use of the pool.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return Stats{
+		Workers: cap(p.slots),
+		Running: len(p.slots),
+		Queued:  p.queued,
+		Waiting: p.waiting,
+	}
+}
+
+func (p *Pool) wait(ctx context.Context) (func(), error) {
+	select {
+	case p.slots <- struct{}{}:
+		return p.releaser(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// releaser returns the function releasing a worker, which does nothing when called again.
+func (p *Pool) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-p.slots })
+	}
+}
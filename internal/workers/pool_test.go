@@ -0,0 +1,71 @@
+package workers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	pool := NewPool(1, 1)
+
+	release, err := pool.Acquire(context.TODO())
+	require.NoError(t, err)
+
+	// The second caller waits for the worker
+	acquired := make(chan func())
+	go func() {
+		release, err := pool.Acquire(context.TODO())
+		assert.NoError(t, err)
+		acquired <- release
+	}()
+
+	assert.Eventually(t, func() bool {
+		return pool.Stats() == Stats{Workers: 1, Running: 1, Queued: 1}
+	}, time.Second, time.Millisecond)
+
+	// The third one finds the queue full
+	_, err = pool.Acquire(context.TODO())
+	require.ErrorIs(t, err, ErrQueueFull)
+
+	// Releasing twice frees a single worker
+	release()
+	release()
+
+	release = <-acquired
+	assert.Equal(t, Stats{Workers: 1, Running: 1}, pool.Stats())
+
+	release()
+	assert.Equal(t, Stats{Workers: 1}, pool.Stats())
+}
+
+func TestPoolWait(t *testing.T) {
+	pool := NewPool(1, 0)
+
+	release, err := pool.Acquire(context.TODO())
+	require.NoError(t, err)
+	defer release()
+
+	_, err = pool.Acquire(context.TODO())
+	require.ErrorIs(t, err, ErrQueueFull)
+
+	// Wait isn't bounded by the queue length, but gives up with its context
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() {
+		_, err := pool.Wait(ctx)
+		done <- err
+	}()
+
+	assert.Eventually(t, func() bool {
+		return pool.Stats().Waiting == 1
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	assert.Zero(t, pool.Stats().Waiting)
+}